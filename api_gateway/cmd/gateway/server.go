@@ -2,17 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	authpb "github.com/MikePham0630/gomicro/auth"
 	mmpb "github.com/MikePham0630/gomicro/money_movement"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var mmClient mmpb.MoneyMovementServiceClient
@@ -45,8 +49,24 @@ func main() {
 	mmClient = mmpb.NewMoneyMovementServiceClient(mmConn)
 
 	http.HandleFunc("/login", login)
-	http.HandleFunc("/customer/payment/authorize", customerPaymentAuthorize)
-	http.HandleFunc("/customer/payment/capture", customerPaymentCapture)
+	http.HandleFunc("POST /register", register)
+	http.HandleFunc("POST /customer/payment/authorize", customerPaymentAuthorize)
+	http.HandleFunc("POST /customer/payment/increment", customerPaymentIncrement)
+	http.HandleFunc("POST /customer/payment/capture", customerPaymentCapture)
+	http.HandleFunc("POST /customer/payment/void", customerPaymentVoid)
+	http.HandleFunc("GET /customer/payment/{pid}", customerPaymentGet)
+	http.HandleFunc("GET /customer/payments", customerPaymentList)
+	http.HandleFunc("GET /customer/balance", customerBalance)
+	http.HandleFunc("POST /customer/deposit", customerDeposit)
+	http.HandleFunc("POST /customer/withdraw", customerWithdraw)
+	http.HandleFunc("POST /customer/transfer", customerTransfer)
+	http.HandleFunc("/merchant/payment/refund", merchantPaymentRefund)
+	http.HandleFunc("GET /merchant/settlements", merchantSettlements)
+	http.HandleFunc("/customer/fx/quote", customerFxQuote)
+	http.HandleFunc("/admin/fx/rates", adminFxRates)
+	http.HandleFunc("POST /admin/fees", adminFees)
+	http.HandleFunc("GET /admin/journal/verify", adminJournalVerify)
+	http.HandleFunc("POST /admin/velocity-limits", adminVelocityLimits)
 
 	fmt.Printf("Listening on port 8080")
 	errL := http.ListenAndServe(":8080", nil)
@@ -77,6 +97,81 @@ func login(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// register creates the user in auth and provisions their wallet, then logs them in.
+func register(w http.ResponseWriter, r *http.Request) {
+	userName, passowrd, ok := r.BasicAuth()
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	walletType := r.URL.Query().Get("wallet_type")
+	if walletType == "" {
+		walletType = "CUSTOMER"
+	}
+
+	// Merchants register with the role that lets them capture and refund
+	role := "CUSTOMER"
+	if walletType == "MERCHANT" {
+		role = "MERCHANT"
+	}
+
+	ctx := context.Background()
+	user, err := authClient.Register(ctx, &authpb.Credentials{Username: userName, Password: passowrd, Role: role})
+	if err != nil {
+		_, errWrite := w.Write([]byte(err.Error()))
+		if errWrite != nil {
+			log.Printf("Error writing response: %s", errWrite)
+		}
+		return
+	}
+
+	// The user only exists together with a wallet of their own. A wallet that
+	// already exists belongs to someone else, so registration fails and the
+	// user is removed again.
+	_, err = mmClient.CreateWallet(withCaller(ctx, user), &mmpb.CreateWalletPayload{UserId: user.UserId, WalletType: walletType})
+	if err != nil {
+		log.Printf("Error creating wallet for %s: %s", user.UserId, err)
+		_, unregisterErr := authClient.Unregister(ctx, &authpb.Credentials{Username: userName, Password: passowrd})
+		if unregisterErr != nil {
+			log.Printf("Error removing user %s after failed registration: %s", user.UserId, unregisterErr)
+		}
+		_, errWrite := w.Write([]byte(err.Error()))
+		if errWrite != nil {
+			log.Printf("Error writing response: %s", errWrite)
+		}
+		return
+	}
+
+	token, err := authClient.GetToken(ctx, &authpb.Credentials{Username: userName, Password: passowrd})
+	if err != nil {
+		_, errWrite := w.Write([]byte(err.Error()))
+		if errWrite != nil {
+			log.Printf("Error writing response: %s", errWrite)
+		}
+		return
+	}
+	_, err = w.Write([]byte(token.Jwt))
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+	}
+}
+
+// withCaller forwards the identity of the validated token to money_movement,
+// which checks that the caller may act on the wallets and payments involved.
+func withCaller(ctx context.Context, user *authpb.User) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-user-id", user.GetUserId(), "x-user-role", user.GetRole())
+}
+
+// withIdempotencyKey forwards the request's Idempotency-Key header to money_movement.
+func withIdempotencyKey(ctx context.Context, r *http.Request) context.Context {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "idempotency-key", key)
+}
+
 func customerPaymentAuthorize(w http.ResponseWriter, r *http.Request) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -91,17 +186,25 @@ func customerPaymentAuthorize(w http.ResponseWriter, r *http.Request) {
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 	ctx := context.Background()
-	_, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
+	ctx = withCaller(ctx, user)
 
-	type authorizePayload struct {
-		CustomerWalletUserId string `json:"customer_wallet_user_id"`
+	type paymentLeg struct {
 		MerchantWalletUserId string `json:"merchant_wallet_user_id"`
 		Cents                int64  `json:"cents"`
-		Currency             string `json:"currency"`
+	}
+
+	type authorizePayload struct {
+		CustomerWalletUserId string       `json:"customer_wallet_user_id"`
+		MerchantWalletUserId string       `json:"merchant_wallet_user_id"`
+		Cents                int64        `json:"cents"`
+		Currency             string       `json:"currency"`
+		FxQuoteId            string       `json:"fx_quote_id"`
+		Legs                 []paymentLeg `json:"legs"`
 	}
 
 	var payload authorizePayload
@@ -117,7 +220,13 @@ func customerPaymentAuthorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ar, err := mmClient.Authorize(ctx, &mmpb.AuthorizePayload{CustomerWalletUserId: payload.CustomerWalletUserId, MerchantWalletUserId: payload.MerchantWalletUserId, Cents: payload.Cents, Currency: payload.Currency})
+	legs := make([]*mmpb.PaymentLeg, 0, len(payload.Legs))
+	for _, leg := range payload.Legs {
+		legs = append(legs, &mmpb.PaymentLeg{MerchantWalletUserId: leg.MerchantWalletUserId, Cents: leg.Cents})
+	}
+
+	ctx = withIdempotencyKey(ctx, r)
+	ar, err := mmClient.Authorize(ctx, &mmpb.AuthorizePayload{CustomerWalletUserId: payload.CustomerWalletUserId, MerchantWalletUserId: payload.MerchantWalletUserId, Cents: payload.Cents, Currency: payload.Currency, FxQuoteId: payload.FxQuoteId, Legs: legs})
 	if err != nil {
 		_, writeErr := w.Write([]byte(err.Error()))
 		if writeErr != nil {
@@ -127,11 +236,13 @@ func customerPaymentAuthorize(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type response struct {
-		Pid string `json:"pid"`
+		Pid          string `json:"pid"`
+		RiskDecision string `json:"risk_decision"`
 	}
 
 	resp := response{
-		Pid: ar.Pid,
+		Pid:          ar.Pid,
+		RiskDecision: ar.RiskDecision,
 	}
 
 	resJSON, err := json.Marshal(resp)
@@ -151,6 +262,81 @@ func customerPaymentAuthorize(w http.ResponseWriter, r *http.Request) {
 	//res.Pid
 }
 
+func customerPaymentIncrement(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type incrementPayload struct {
+		Pid             string `json:"pid"`
+		AdditionalCents int64  `json:"additional_cents"`
+	}
+
+	var payload incrementPayload
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	ctx = withIdempotencyKey(ctx, r)
+	ir, err := mmClient.IncrementAuthorization(ctx, &mmpb.IncrementAuthorizationPayload{Pid: payload.Pid, AdditionalCents: payload.AdditionalCents})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type response struct {
+		Pid             string `json:"pid"`
+		AuthorizedCents int64  `json:"authorized_cents"`
+		RemainingCents  int64  `json:"remaining_cents"`
+	}
+
+	resJSON, err := json.Marshal(response{
+		Pid:             ir.Pid,
+		AuthorizedCents: ir.AuthorizedCents,
+		RemainingCents:  ir.RemainingCents,
+	})
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+
+}
+
 func customerPaymentCapture(w http.ResponseWriter, r *http.Request) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -165,14 +351,17 @@ func customerPaymentCapture(w http.ResponseWriter, r *http.Request) {
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 	ctx := context.Background()
-	_, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
+	ctx = withCaller(ctx, user)
 
 	type capturePayload struct {
-		Pid string `json:"pid"`
+		Pid          string `json:"pid"`
+		Cents        int64  `json:"cents"`
+		FinalCapture bool   `json:"final_capture"`
 	}
 
 	var payload capturePayload
@@ -188,7 +377,133 @@ func customerPaymentCapture(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = mmClient.Capture(ctx, &mmpb.CapturePayload{Pid: payload.Pid})
+	ctx = withIdempotencyKey(ctx, r)
+	cr, err := mmClient.Capture(ctx, &mmpb.CapturePayload{Pid: payload.Pid, Cents: payload.Cents, FinalCapture: payload.FinalCapture})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type capturedLeg struct {
+		MerchantWalletUserId string `json:"merchant_wallet_user_id"`
+		Cents                int64  `json:"cents"`
+		MerchantCents        int64  `json:"merchant_cents"`
+		FeeCents             int64  `json:"fee_cents"`
+	}
+
+	type response struct {
+		CaptureId      string        `json:"capture_id"`
+		RemainingCents int64         `json:"remaining_cents"`
+		FeeCents       int64         `json:"fee_cents"`
+		Legs           []capturedLeg `json:"legs"`
+	}
+
+	resp := response{
+		CaptureId:      cr.CaptureId,
+		RemainingCents: cr.RemainingCents,
+		FeeCents:       cr.FeeCents,
+		Legs:           []capturedLeg{},
+	}
+	for _, leg := range cr.Legs {
+		resp.Legs = append(resp.Legs, capturedLeg{
+			MerchantWalletUserId: leg.MerchantWalletUserId,
+			Cents:                leg.Cents,
+			MerchantCents:        leg.MerchantCents,
+			FeeCents:             leg.FeeCents,
+		})
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+
+}
+
+func customerPaymentVoid(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type voidPayload struct {
+		Pid string `json:"pid"`
+	}
+
+	var payload voidPayload
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = mmClient.Void(ctx, &mmpb.VoidPayload{Pid: payload.Pid})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func customerPaymentGet(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	payment, err := mmClient.GetPayment(ctx, &mmpb.GetPaymentPayload{Pid: r.PathValue("pid")})
 	if err != nil {
 		_, writeErr := w.Write([]byte(err.Error()))
 		if writeErr != nil {
@@ -197,6 +512,973 @@ func customerPaymentCapture(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	type paymentLeg struct {
+		MerchantWalletUserId string `json:"merchant_wallet_user_id"`
+		Cents                int64  `json:"cents"`
+		RefundedCents        int64  `json:"refunded_cents"`
+	}
+
+	type response struct {
+		Pid                   string       `json:"pid"`
+		Status                string       `json:"status"`
+		CustomerWalletUserId  string       `json:"customer_wallet_user_id"`
+		MerchantWalletUserId  string       `json:"merchant_wallet_user_id"`
+		Currency              string       `json:"currency"`
+		MerchantCurrency      string       `json:"merchant_currency"`
+		AuthorizedCents       int64        `json:"authorized_cents"`
+		CapturedCents         int64        `json:"captured_cents"`
+		MerchantCapturedCents int64        `json:"merchant_captured_cents"`
+		ReleasedCents         int64        `json:"released_cents"`
+		RefundedCents         int64        `json:"refunded_cents"`
+		RiskDecision          string       `json:"risk_decision"`
+		RiskReasons           []string     `json:"risk_reasons"`
+		Legs                  []paymentLeg `json:"legs,omitempty"`
+		CreatedAt             int64        `json:"created_at"`
+		UpdatedAt             int64        `json:"updated_at"`
+	}
+
+	resp := response{
+		Pid:                   payment.Pid,
+		Status:                payment.Status,
+		CustomerWalletUserId:  payment.CustomerWalletUserId,
+		MerchantWalletUserId:  payment.MerchantWalletUserId,
+		Currency:              payment.Currency,
+		MerchantCurrency:      payment.MerchantCurrency,
+		AuthorizedCents:       payment.AuthorizedCents,
+		CapturedCents:         payment.CapturedCents,
+		MerchantCapturedCents: payment.MerchantCapturedCents,
+		ReleasedCents:         payment.ReleasedCents,
+		RefundedCents:         payment.RefundedCents,
+		RiskDecision:          payment.RiskDecision,
+		RiskReasons:           payment.RiskReasons,
+		CreatedAt:             payment.CreatedAt,
+		UpdatedAt:             payment.UpdatedAt,
+	}
+	for _, leg := range payment.Legs {
+		resp.Legs = append(resp.Legs, paymentLeg{MerchantWalletUserId: leg.MerchantWalletUserId, Cents: leg.Cents, RefundedCents: leg.RefundedCents})
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func customerPaymentList(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	// Customers only ever see transactions of their own wallet
+	query := r.URL.Query()
+	payload := &mmpb.ListTransactionsPayload{
+		UserId:      user.UserId,
+		AccountType: query.Get("account_type"),
+		Status:      query.Get("status"),
+		PageToken:   query.Get("page_token"),
+	}
+
+	if v := query.Get("wallet_id"); v != "" {
+		walletId, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+		payload.WalletId = int32(walletId)
+	}
+	if v := query.Get("from"); v != "" {
+		payload.CreatedFrom, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		payload.CreatedTo, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+		payload.PageSize = int32(pageSize)
+	}
+
+	list, err := mmClient.ListTransactions(ctx, payload)
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type transaction struct {
+		Id                int32  `json:"id"`
+		Pid               string `json:"pid"`
+		TransactionType   string `json:"transaction_type"`
+		CaptureId         string `json:"capture_id,omitempty"`
+		Status            string `json:"status,omitempty"`
+		SrcUserId         string `json:"src_user_id"`
+		DstUserId         string `json:"dst_user_id"`
+		SrcWalletId       int32  `json:"src_wallet_id"`
+		DstWalletId       int32  `json:"dst_wallet_id"`
+		SrcAccountType    string `json:"src_account_type"`
+		DstAccountType    string `json:"dst_account_type"`
+		Amount            int64  `json:"amount"`
+		Currency          string `json:"currency"`
+		ConvertedAmount   int64  `json:"converted_amount"`
+		ConvertedCurrency string `json:"converted_currency"`
+		Memo              string `json:"memo,omitempty"`
+		CreatedAt         int64  `json:"created_at"`
+	}
+
+	type response struct {
+		Transactions  []transaction `json:"transactions"`
+		NextPageToken string        `json:"next_page_token,omitempty"`
+	}
+
+	resp := response{
+		Transactions:  make([]transaction, 0, len(list.Transactions)),
+		NextPageToken: list.NextPageToken,
+	}
+	for _, t := range list.Transactions {
+		resp.Transactions = append(resp.Transactions, transaction{
+			Id:                t.Id,
+			Pid:               t.Pid,
+			TransactionType:   t.TransactionType,
+			CaptureId:         t.CaptureId,
+			Status:            t.Status,
+			SrcUserId:         t.SrcUserId,
+			DstUserId:         t.DstUserId,
+			SrcWalletId:       t.SrcWalletId,
+			DstWalletId:       t.DstWalletId,
+			SrcAccountType:    t.SrcAccountType,
+			DstAccountType:    t.DstAccountType,
+			Amount:            t.Amount,
+			Currency:          t.Currency,
+			ConvertedAmount:   t.ConvertedAmount,
+			ConvertedCurrency: t.ConvertedCurrency,
+			Memo:              t.Memo,
+			CreatedAt:         t.CreatedAt,
+		})
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func customerBalance(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	// Balances are always those of the token's subject, never a requested user
+	balances, err := mmClient.GetBalances(ctx, &mmpb.GetBalancesPayload{UserId: user.UserId})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type accountBalance struct {
+		AccountType    string `json:"account_type"`
+		Currency       string `json:"currency"`
+		AvailableCents int64  `json:"available_cents"`
+		HeldCents      int64  `json:"held_cents"`
+	}
+
+	type response struct {
+		UserId     string           `json:"user_id"`
+		WalletId   int32            `json:"wallet_id"`
+		WalletType string           `json:"wallet_type"`
+		Accounts   []accountBalance `json:"accounts"`
+	}
+
+	resp := response{
+		UserId:     balances.UserId,
+		WalletId:   balances.WalletId,
+		WalletType: balances.WalletType,
+		Accounts:   make([]accountBalance, 0, len(balances.Accounts)),
+	}
+	for _, a := range balances.Accounts {
+		resp.Accounts = append(resp.Accounts, accountBalance{
+			AccountType:    a.AccountType,
+			Currency:       a.Currency,
+			AvailableCents: a.AvailableCents,
+			HeldCents:      a.HeldCents,
+		})
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func customerDeposit(w http.ResponseWriter, r *http.Request) {
+	customerFunding(w, r, mmClient.Deposit)
+}
+
+func customerWithdraw(w http.ResponseWriter, r *http.Request) {
+	customerFunding(w, r, mmClient.Withdraw)
+}
+
+// customerFunding moves money between the caller's wallet and their bank with fund.
+func customerFunding(w http.ResponseWriter, r *http.Request, fund func(context.Context, *mmpb.FundingPayload, ...grpc.CallOption) (*mmpb.Funding, error)) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type fundingPayload struct {
+		Cents    int64  `json:"cents"`
+		Currency string `json:"currency"`
+	}
+
+	var payload fundingPayload
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	ctx = withIdempotencyKey(ctx, r)
+	funding, err := fund(ctx, &mmpb.FundingPayload{UserId: user.UserId, Cents: payload.Cents, Currency: payload.Currency})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type response struct {
+		FundingId     string `json:"funding_id"`
+		Type          string `json:"type"`
+		Status        string `json:"status"`
+		Cents         int64  `json:"cents"`
+		Currency      string `json:"currency"`
+		FailureReason string `json:"failure_reason,omitempty"`
+	}
+
+	resp := response{
+		FundingId:     funding.FundingId,
+		Type:          funding.Type,
+		Status:        funding.Status,
+		Cents:         funding.Cents,
+		Currency:      funding.Currency,
+		FailureReason: funding.FailureReason,
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func customerTransfer(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type transferPayload struct {
+		ToUserId string `json:"to_user_id"`
+		Cents    int64  `json:"cents"`
+		Currency string `json:"currency"`
+		Memo     string `json:"memo"`
+	}
+
+	var payload transferPayload
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	// Customers can only send money from their own wallet
+	ctx = withIdempotencyKey(ctx, r)
+	res, err := mmClient.Transfer(ctx, &mmpb.TransferPayload{FromUserId: user.UserId, ToUserId: payload.ToUserId, Cents: payload.Cents, Currency: payload.Currency, Memo: payload.Memo})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type response struct {
+		TransferId string `json:"transfer_id"`
+	}
+
+	resp := response{
+		TransferId: res.TransferId,
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func merchantPaymentRefund(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type refundPayload struct {
+		Pid                  string `json:"pid"`
+		Cents                int64  `json:"cents"`
+		Reason               string `json:"reason"`
+		MerchantWalletUserId string `json:"merchant_wallet_user_id"`
+	}
+
+	var payload refundPayload
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = mmClient.Refund(ctx, &mmpb.RefundPayload{Pid: payload.Pid, Cents: payload.Cents, Reason: payload.Reason, MerchantWalletUserId: payload.MerchantWalletUserId})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// merchantSettlements reports the caller's settlements as JSON, or as CSV with
+// one row per included transaction when format=csv.
+func merchantSettlements(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	query := r.URL.Query()
+	payload := &mmpb.ListSettlementsPayload{MerchantUserId: user.UserId}
+	if v := query.Get("from"); v != "" {
+		payload.CreatedFrom, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		payload.CreatedTo, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := mmClient.ListSettlements(ctx, payload)
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="settlements.csv"`)
+		w.WriteHeader(http.StatusOK)
+
+		cw := csv.NewWriter(w)
+		rows := [][]string{{"settlement_id", "created_at", "cutoff", "currency", "settlement_cents", "pid", "transaction_id", "transaction_type", "cents"}}
+		for _, s := range list.Settlements {
+			for _, item := range s.Items {
+				rows = append(rows, []string{
+					s.SettlementId,
+					strconv.FormatInt(s.CreatedAt, 10),
+					strconv.FormatInt(s.Cutoff, 10),
+					s.Currency,
+					strconv.FormatInt(s.Cents, 10),
+					item.Pid,
+					strconv.FormatInt(int64(item.TransactionId), 10),
+					item.TransactionType,
+					strconv.FormatInt(item.Cents, 10),
+				})
+			}
+		}
+		err = cw.WriteAll(rows)
+		if err != nil {
+			log.Printf("Error writing response: %s", err)
+		}
+		return
+	}
+
+	type settlementItem struct {
+		Pid             string `json:"pid"`
+		TransactionId   int32  `json:"transaction_id"`
+		TransactionType string `json:"transaction_type"`
+		Cents           int64  `json:"cents"`
+	}
+
+	type settlement struct {
+		SettlementId string           `json:"settlement_id"`
+		Currency     string           `json:"currency"`
+		Cents        int64            `json:"cents"`
+		Cutoff       int64            `json:"cutoff"`
+		CreatedAt    int64            `json:"created_at"`
+		Items        []settlementItem `json:"items"`
+	}
+
+	type response struct {
+		Settlements []settlement `json:"settlements"`
+	}
+
+	resp := response{
+		Settlements: make([]settlement, 0, len(list.Settlements)),
+	}
+	for _, s := range list.Settlements {
+		items := make([]settlementItem, 0, len(s.Items))
+		for _, item := range s.Items {
+			items = append(items, settlementItem{
+				Pid:             item.Pid,
+				TransactionId:   item.TransactionId,
+				TransactionType: item.TransactionType,
+				Cents:           item.Cents,
+			})
+		}
+		resp.Settlements = append(resp.Settlements, settlement{
+			SettlementId: s.SettlementId,
+			Currency:     s.Currency,
+			Cents:        s.Cents,
+			Cutoff:       s.Cutoff,
+			CreatedAt:    s.CreatedAt,
+			Items:        items,
+		})
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func customerFxQuote(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type fxQuotePayload struct {
+		SrcCurrency string `json:"src_currency"`
+		DstCurrency string `json:"dst_currency"`
+		SrcCents    int64  `json:"src_cents"`
+	}
+
+	var payload fxQuotePayload
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	quote, err := mmClient.CreateFxQuote(ctx, &mmpb.FxQuotePayload{SrcCurrency: payload.SrcCurrency, DstCurrency: payload.DstCurrency, SrcCents: payload.SrcCents})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type response struct {
+		QuoteId     string `json:"quote_id"`
+		SrcCurrency string `json:"src_currency"`
+		DstCurrency string `json:"dst_currency"`
+		SrcCents    int64  `json:"src_cents"`
+		DstCents    int64  `json:"dst_cents"`
+		Rate        string `json:"rate"`
+		ExpiresAt   int64  `json:"expires_at"`
+	}
+
+	resp := response{
+		QuoteId:     quote.QuoteId,
+		SrcCurrency: quote.SrcCurrency,
+		DstCurrency: quote.DstCurrency,
+		SrcCents:    quote.SrcCents,
+		DstCents:    quote.DstCents,
+		Rate:        quote.Rate,
+		ExpiresAt:   quote.ExpiresAt,
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func adminFxRates(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	type fxRate struct {
+		BaseCurrency  string `json:"base_currency"`
+		QuoteCurrency string `json:"quote_currency"`
+		Rate          string `json:"rate"`
+		SpreadBps     int32  `json:"spread_bps"`
+	}
+
+	var payload struct {
+		Rates []fxRate `json:"rates"`
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	rates := make([]*mmpb.FxRate, 0, len(payload.Rates))
+	for _, rate := range payload.Rates {
+		rates = append(rates, &mmpb.FxRate{BaseCurrency: rate.BaseCurrency, QuoteCurrency: rate.QuoteCurrency, Rate: rate.Rate, SpreadBps: rate.SpreadBps})
+	}
+
+	_, err = mmClient.SetFxRates(ctx, &mmpb.SetFxRatesPayload{Rates: rates})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func adminFees(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	var payload struct {
+		MerchantUserId string `json:"merchant_user_id"`
+		Currency       string `json:"currency"`
+		PercentBps     int32  `json:"percent_bps"`
+		FixedCents     int64  `json:"fixed_cents"`
+		MinCents       int64  `json:"min_cents"`
+		MaxCents       int64  `json:"max_cents"`
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = mmClient.SetFeeSchedule(ctx, &mmpb.FeeSchedule{
+		MerchantUserId: payload.MerchantUserId,
+		Currency:       payload.Currency,
+		PercentBps:     payload.PercentBps,
+		FixedCents:     payload.FixedCents,
+		MinCents:       payload.MinCents,
+		MaxCents:       payload.MaxCents,
+	})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func adminJournalVerify(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	report, err := mmClient.VerifyJournal(ctx, &emptypb.Empty{})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	type accountDrift struct {
+		AccountId    int32  `json:"account_id"`
+		WalletId     int32  `json:"wallet_id"`
+		AccountType  string `json:"account_type"`
+		Currency     string `json:"currency"`
+		Cents        int64  `json:"cents"`
+		JournalCents int64  `json:"journal_cents"`
+	}
+
+	type response struct {
+		AccountsChecked    int32          `json:"accounts_checked"`
+		Drifts             []accountDrift `json:"drifts"`
+		UnbalancedEntryIds []int64        `json:"unbalanced_entry_ids"`
+	}
+
+	resp := response{
+		AccountsChecked:    report.AccountsChecked,
+		Drifts:             make([]accountDrift, 0, len(report.Drifts)),
+		UnbalancedEntryIds: append([]int64{}, report.UnbalancedEntryIds...),
+	}
+	for _, d := range report.Drifts {
+		resp.Drifts = append(resp.Drifts, accountDrift{
+			AccountId:    d.AccountId,
+			WalletId:     d.WalletId,
+			AccountType:  d.AccountType,
+			Currency:     d.Currency,
+			Cents:        d.Cents,
+			JournalCents: d.JournalCents,
+		})
+	}
+
+	resJSON, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resJSON)
+	if err != nil {
+		log.Printf("Error writing response: %s", err)
+		return
+	}
+}
+
+func adminVelocityLimits(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	ctx := context.Background()
+	user, err := authClient.ValidateToken(ctx, &authpb.Token{Jwt: token})
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ctx = withCaller(ctx, user)
+
+	var payload struct {
+		WalletType            string `json:"wallet_type"`
+		UserId                string `json:"user_id"`
+		Currency              string `json:"currency"`
+		MaxSingleCents        int64  `json:"max_single_cents"`
+		MaxDailyCents         int64  `json:"max_daily_cents"`
+		MaxMonthlyCents       int64  `json:"max_monthly_cents"`
+		MaxHourlyCount        int32  `json:"max_hourly_count"`
+		MaxDailyTransferCents int64  `json:"max_daily_transfer_cents"`
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = mmClient.SetVelocityLimit(ctx, &mmpb.VelocityLimit{
+		WalletType:            payload.WalletType,
+		UserId:                payload.UserId,
+		Currency:              payload.Currency,
+		MaxSingleCents:        payload.MaxSingleCents,
+		MaxDailyCents:         payload.MaxDailyCents,
+		MaxMonthlyCents:       payload.MaxMonthlyCents,
+		MaxHourlyCount:        payload.MaxHourlyCount,
+		MaxDailyTransferCents: payload.MaxDailyTransferCents,
+	})
+	if err != nil {
+		_, writeErr := w.Write([]byte(err.Error()))
+		if writeErr != nil {
+			log.Printf("Error writing response: %s", writeErr)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
@@ -24,7 +24,24 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MoneyMovementServiceClient interface {
 	Authorize(ctx context.Context, in *AuthorizePayload, opts ...grpc.CallOption) (*AuthorizationResponse, error)
-	Capture(ctx context.Context, in *CapturePayload, opts ...grpc.CallOption) (*empty.Empty, error)
+	Capture(ctx context.Context, in *CapturePayload, opts ...grpc.CallOption) (*CaptureResponse, error)
+	Void(ctx context.Context, in *VoidPayload, opts ...grpc.CallOption) (*empty.Empty, error)
+	Refund(ctx context.Context, in *RefundPayload, opts ...grpc.CallOption) (*empty.Empty, error)
+	SetFxRates(ctx context.Context, in *SetFxRatesPayload, opts ...grpc.CallOption) (*empty.Empty, error)
+	CreateFxQuote(ctx context.Context, in *FxQuotePayload, opts ...grpc.CallOption) (*FxQuote, error)
+	GetPayment(ctx context.Context, in *GetPaymentPayload, opts ...grpc.CallOption) (*Payment, error)
+	ListTransactions(ctx context.Context, in *ListTransactionsPayload, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	GetBalances(ctx context.Context, in *GetBalancesPayload, opts ...grpc.CallOption) (*Balances, error)
+	CreateWallet(ctx context.Context, in *CreateWalletPayload, opts ...grpc.CallOption) (*Wallet, error)
+	CloseWallet(ctx context.Context, in *CloseWalletPayload, opts ...grpc.CallOption) (*empty.Empty, error)
+	Deposit(ctx context.Context, in *FundingPayload, opts ...grpc.CallOption) (*Funding, error)
+	Withdraw(ctx context.Context, in *FundingPayload, opts ...grpc.CallOption) (*Funding, error)
+	Transfer(ctx context.Context, in *TransferPayload, opts ...grpc.CallOption) (*TransferResponse, error)
+	ListSettlements(ctx context.Context, in *ListSettlementsPayload, opts ...grpc.CallOption) (*ListSettlementsResponse, error)
+	SetFeeSchedule(ctx context.Context, in *FeeSchedule, opts ...grpc.CallOption) (*empty.Empty, error)
+	VerifyJournal(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*JournalReport, error)
+	SetVelocityLimit(ctx context.Context, in *VelocityLimit, opts ...grpc.CallOption) (*empty.Empty, error)
+	IncrementAuthorization(ctx context.Context, in *IncrementAuthorizationPayload, opts ...grpc.CallOption) (*IncrementAuthorizationResponse, error)
 }
 
 type moneyMovementServiceClient struct {
@@ -44,8 +61,8 @@ func (c *moneyMovementServiceClient) Authorize(ctx context.Context, in *Authoriz
 	return out, nil
 }
 
-func (c *moneyMovementServiceClient) Capture(ctx context.Context, in *CapturePayload, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
+func (c *moneyMovementServiceClient) Capture(ctx context.Context, in *CapturePayload, opts ...grpc.CallOption) (*CaptureResponse, error) {
+	out := new(CaptureResponse)
 	err := c.cc.Invoke(ctx, "/MoneyMovementService/Capture", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -53,12 +70,182 @@ func (c *moneyMovementServiceClient) Capture(ctx context.Context, in *CapturePay
 	return out, nil
 }
 
+func (c *moneyMovementServiceClient) Void(ctx context.Context, in *VoidPayload, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/Void", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) Refund(ctx context.Context, in *RefundPayload, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/Refund", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) SetFxRates(ctx context.Context, in *SetFxRatesPayload, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/SetFxRates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) CreateFxQuote(ctx context.Context, in *FxQuotePayload, opts ...grpc.CallOption) (*FxQuote, error) {
+	out := new(FxQuote)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/CreateFxQuote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) GetPayment(ctx context.Context, in *GetPaymentPayload, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/GetPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsPayload, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/ListTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) GetBalances(ctx context.Context, in *GetBalancesPayload, opts ...grpc.CallOption) (*Balances, error) {
+	out := new(Balances)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/GetBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) CreateWallet(ctx context.Context, in *CreateWalletPayload, opts ...grpc.CallOption) (*Wallet, error) {
+	out := new(Wallet)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/CreateWallet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) CloseWallet(ctx context.Context, in *CloseWalletPayload, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/CloseWallet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) Deposit(ctx context.Context, in *FundingPayload, opts ...grpc.CallOption) (*Funding, error) {
+	out := new(Funding)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/Deposit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) Withdraw(ctx context.Context, in *FundingPayload, opts ...grpc.CallOption) (*Funding, error) {
+	out := new(Funding)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/Withdraw", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) Transfer(ctx context.Context, in *TransferPayload, opts ...grpc.CallOption) (*TransferResponse, error) {
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/Transfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) ListSettlements(ctx context.Context, in *ListSettlementsPayload, opts ...grpc.CallOption) (*ListSettlementsResponse, error) {
+	out := new(ListSettlementsResponse)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/ListSettlements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) SetFeeSchedule(ctx context.Context, in *FeeSchedule, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/SetFeeSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) VerifyJournal(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*JournalReport, error) {
+	out := new(JournalReport)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/VerifyJournal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) SetVelocityLimit(ctx context.Context, in *VelocityLimit, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/SetVelocityLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyMovementServiceClient) IncrementAuthorization(ctx context.Context, in *IncrementAuthorizationPayload, opts ...grpc.CallOption) (*IncrementAuthorizationResponse, error) {
+	out := new(IncrementAuthorizationResponse)
+	err := c.cc.Invoke(ctx, "/MoneyMovementService/IncrementAuthorization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MoneyMovementServiceServer is the server API for MoneyMovementService service.
 // All implementations must embed UnimplementedMoneyMovementServiceServer
 // for forward compatibility
 type MoneyMovementServiceServer interface {
 	Authorize(context.Context, *AuthorizePayload) (*AuthorizationResponse, error)
-	Capture(context.Context, *CapturePayload) (*empty.Empty, error)
+	Capture(context.Context, *CapturePayload) (*CaptureResponse, error)
+	Void(context.Context, *VoidPayload) (*empty.Empty, error)
+	Refund(context.Context, *RefundPayload) (*empty.Empty, error)
+	SetFxRates(context.Context, *SetFxRatesPayload) (*empty.Empty, error)
+	CreateFxQuote(context.Context, *FxQuotePayload) (*FxQuote, error)
+	GetPayment(context.Context, *GetPaymentPayload) (*Payment, error)
+	ListTransactions(context.Context, *ListTransactionsPayload) (*ListTransactionsResponse, error)
+	GetBalances(context.Context, *GetBalancesPayload) (*Balances, error)
+	CreateWallet(context.Context, *CreateWalletPayload) (*Wallet, error)
+	CloseWallet(context.Context, *CloseWalletPayload) (*empty.Empty, error)
+	Deposit(context.Context, *FundingPayload) (*Funding, error)
+	Withdraw(context.Context, *FundingPayload) (*Funding, error)
+	Transfer(context.Context, *TransferPayload) (*TransferResponse, error)
+	ListSettlements(context.Context, *ListSettlementsPayload) (*ListSettlementsResponse, error)
+	SetFeeSchedule(context.Context, *FeeSchedule) (*empty.Empty, error)
+	VerifyJournal(context.Context, *empty.Empty) (*JournalReport, error)
+	SetVelocityLimit(context.Context, *VelocityLimit) (*empty.Empty, error)
+	IncrementAuthorization(context.Context, *IncrementAuthorizationPayload) (*IncrementAuthorizationResponse, error)
 	mustEmbedUnimplementedMoneyMovementServiceServer()
 }
 
@@ -69,9 +256,60 @@ type UnimplementedMoneyMovementServiceServer struct {
 func (UnimplementedMoneyMovementServiceServer) Authorize(context.Context, *AuthorizePayload) (*AuthorizationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
 }
-func (UnimplementedMoneyMovementServiceServer) Capture(context.Context, *CapturePayload) (*empty.Empty, error) {
+func (UnimplementedMoneyMovementServiceServer) Capture(context.Context, *CapturePayload) (*CaptureResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Capture not implemented")
 }
+func (UnimplementedMoneyMovementServiceServer) Void(context.Context, *VoidPayload) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Void not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) Refund(context.Context, *RefundPayload) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refund not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) SetFxRates(context.Context, *SetFxRatesPayload) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFxRates not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) CreateFxQuote(context.Context, *FxQuotePayload) (*FxQuote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFxQuote not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) GetPayment(context.Context, *GetPaymentPayload) (*Payment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayment not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) ListTransactions(context.Context, *ListTransactionsPayload) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) GetBalances(context.Context, *GetBalancesPayload) (*Balances, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) CreateWallet(context.Context, *CreateWalletPayload) (*Wallet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWallet not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) CloseWallet(context.Context, *CloseWalletPayload) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseWallet not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) Deposit(context.Context, *FundingPayload) (*Funding, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) Withdraw(context.Context, *FundingPayload) (*Funding, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) Transfer(context.Context, *TransferPayload) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) ListSettlements(context.Context, *ListSettlementsPayload) (*ListSettlementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSettlements not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) SetFeeSchedule(context.Context, *FeeSchedule) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFeeSchedule not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) VerifyJournal(context.Context, *empty.Empty) (*JournalReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyJournal not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) SetVelocityLimit(context.Context, *VelocityLimit) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVelocityLimit not implemented")
+}
+func (UnimplementedMoneyMovementServiceServer) IncrementAuthorization(context.Context, *IncrementAuthorizationPayload) (*IncrementAuthorizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IncrementAuthorization not implemented")
+}
 func (UnimplementedMoneyMovementServiceServer) mustEmbedUnimplementedMoneyMovementServiceServer() {}
 
 // UnsafeMoneyMovementServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -121,6 +359,312 @@ func _MoneyMovementService_Capture_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MoneyMovementService_Void_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoidPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).Void(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/Void",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).Void(ctx, req.(*VoidPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_Refund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).Refund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/Refund",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).Refund(ctx, req.(*RefundPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_SetFxRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFxRatesPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).SetFxRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/SetFxRates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).SetFxRates(ctx, req.(*SetFxRatesPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_CreateFxQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FxQuotePayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).CreateFxQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/CreateFxQuote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).CreateFxQuote(ctx, req.(*FxQuotePayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_GetPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).GetPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/GetPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).GetPayment(ctx, req.(*GetPaymentPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/ListTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).ListTransactions(ctx, req.(*ListTransactionsPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/GetBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).GetBalances(ctx, req.(*GetBalancesPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_CreateWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWalletPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).CreateWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/CreateWallet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).CreateWallet(ctx, req.(*CreateWalletPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_CloseWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseWalletPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).CloseWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/CloseWallet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).CloseWallet(ctx, req.(*CloseWalletPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/Deposit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).Deposit(ctx, req.(*FundingPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/Withdraw",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).Withdraw(ctx, req.(*FundingPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/Transfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).Transfer(ctx, req.(*TransferPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_ListSettlements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSettlementsPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).ListSettlements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/ListSettlements",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).ListSettlements(ctx, req.(*ListSettlementsPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_SetFeeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeSchedule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).SetFeeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/SetFeeSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).SetFeeSchedule(ctx, req.(*FeeSchedule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_VerifyJournal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).VerifyJournal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/VerifyJournal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).VerifyJournal(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_SetVelocityLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VelocityLimit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).SetVelocityLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/SetVelocityLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).SetVelocityLimit(ctx, req.(*VelocityLimit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyMovementService_IncrementAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementAuthorizationPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyMovementServiceServer).IncrementAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MoneyMovementService/IncrementAuthorization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyMovementServiceServer).IncrementAuthorization(ctx, req.(*IncrementAuthorizationPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MoneyMovementService_ServiceDesc is the grpc.ServiceDesc for MoneyMovementService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -136,6 +680,74 @@ var MoneyMovementService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Capture",
 			Handler:    _MoneyMovementService_Capture_Handler,
 		},
+		{
+			MethodName: "Void",
+			Handler:    _MoneyMovementService_Void_Handler,
+		},
+		{
+			MethodName: "Refund",
+			Handler:    _MoneyMovementService_Refund_Handler,
+		},
+		{
+			MethodName: "SetFxRates",
+			Handler:    _MoneyMovementService_SetFxRates_Handler,
+		},
+		{
+			MethodName: "CreateFxQuote",
+			Handler:    _MoneyMovementService_CreateFxQuote_Handler,
+		},
+		{
+			MethodName: "GetPayment",
+			Handler:    _MoneyMovementService_GetPayment_Handler,
+		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _MoneyMovementService_ListTransactions_Handler,
+		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _MoneyMovementService_GetBalances_Handler,
+		},
+		{
+			MethodName: "CreateWallet",
+			Handler:    _MoneyMovementService_CreateWallet_Handler,
+		},
+		{
+			MethodName: "CloseWallet",
+			Handler:    _MoneyMovementService_CloseWallet_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _MoneyMovementService_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _MoneyMovementService_Withdraw_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _MoneyMovementService_Transfer_Handler,
+		},
+		{
+			MethodName: "ListSettlements",
+			Handler:    _MoneyMovementService_ListSettlements_Handler,
+		},
+		{
+			MethodName: "SetFeeSchedule",
+			Handler:    _MoneyMovementService_SetFeeSchedule_Handler,
+		},
+		{
+			MethodName: "VerifyJournal",
+			Handler:    _MoneyMovementService_VerifyJournal_Handler,
+		},
+		{
+			MethodName: "SetVelocityLimit",
+			Handler:    _MoneyMovementService_SetVelocityLimit_Handler,
+		},
+		{
+			MethodName: "IncrementAuthorization",
+			Handler:    _MoneyMovementService_IncrementAuthorization_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/money_movement_svc.proto",
@@ -9,7 +9,8 @@ USE auth;
 CREATE TABLE users (
     id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
     user_id VARCHAR(255) NOT NULL UNIQUE,
-    password VARCHAR(255) NOT NULL
+    password VARCHAR(255) NOT NULL,
+    role VARCHAR(255) NOT NULL DEFAULT 'CUSTOMER' -- CUSTOMER, MERCHANT or ADMIN
 );
 
 INSERT INTO users (user_id, password) VALUES
@@ -9,12 +9,21 @@ import (
 	"time"
 
 	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/go-sql-driver/mysql"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	jwt "github.com/golang-jwt/jwt/v5"
 )
 
+// Roles carried in the token and forwarded to money_movement. Admins are
+// only ever granted in the database.
+const (
+	roleCustomer = "CUSTOMER"
+	roleMerchant = "MERCHANT"
+	roleAdmin    = "ADMIN"
+)
+
 type Implementation struct {
 	db *sql.DB
 	pb.UnimplementedAuthServiceServer
@@ -30,16 +39,17 @@ func (this *Implementation) GetToken(ctx context.Context, credentials *pb.Creden
 	type User struct {
 		userID   string
 		password string
+		role     string
 	}
 
 	var u User
 
-	stmt, err := this.db.Prepare("SELECT user_id, password FROM users WHERE user_id = ? AND password = ?")
+	stmt, err := this.db.Prepare("SELECT user_id, password, role FROM users WHERE user_id = ? AND password = ?")
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
 	}
 
-	err = stmt.QueryRow(credentials.GetUsername(), credentials.GetPassword()).Scan(&u.userID, &u.password)
+	err = stmt.QueryRow(credentials.GetUsername(), credentials.GetPassword()).Scan(&u.userID, &u.password, &u.role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, status.Errorf(codes.Unauthenticated, "user not found")
@@ -47,21 +57,80 @@ func (this *Implementation) GetToken(ctx context.Context, credentials *pb.Creden
 		return nil, status.Errorf(codes.Internal, "failed to query user: %v", err)
 	}
 
-	jwt, err := createJWMT(u.userID)
+	jwt, err := createJWMT(u.userID, u.role)
 	return &pb.Token{
 		Jwt: jwt,
 	}, nil
 }
 
-func createJWMT(userID string) (string, error) {
+func (this *Implementation) Register(ctx context.Context, credentials *pb.Credentials) (*pb.User, error) {
+	if credentials.GetUsername() == "" || credentials.GetPassword() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "username and password are required")
+	}
+
+	role := credentials.GetRole()
+	if role == "" {
+		role = roleCustomer
+	}
+	if role != roleCustomer && role != roleMerchant {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot register with role: %s", role)
+	}
+
+	stmt, err := this.db.Prepare("INSERT INTO users (user_id, password, role) VALUES (?, ?, ?)")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	_, err = stmt.Exec(credentials.GetUsername(), credentials.GetPassword(), role)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return nil, status.Errorf(codes.AlreadyExists, "user already exists")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to insert user: %v", err)
+	}
+
+	return &pb.User{
+		UserId: credentials.GetUsername(),
+		Role:   role,
+	}, nil
+}
+
+// Unregister deletes a user that was just registered, e.g. when the gateway
+// could not provision their wallet. Admins cannot be removed this way.
+func (this *Implementation) Unregister(ctx context.Context, credentials *pb.Credentials) (*pb.User, error) {
+	stmt, err := this.db.Prepare("DELETE FROM users WHERE user_id = ? AND password = ? AND role <> ?")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	res, err := stmt.Exec(credentials.GetUsername(), credentials.GetPassword(), roleAdmin)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete user: %v", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete user: %v", err)
+	}
+	if deleted == 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "user not found")
+	}
+
+	return &pb.User{
+		UserId: credentials.GetUsername(),
+	}, nil
+}
+
+func createJWMT(userID, role string) (string, error) {
 	key := []byte(os.Getenv("SIGNING_KEY"))
 	now := time.Now()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss": "auth-service",
-		"sub": userID,
-		"exp": now.Add(24 * time.Hour).Unix(),
-		"iat": now.Unix(),
+		"iss":  "auth-service",
+		"sub":  userID,
+		"role": role,
+		"exp":  now.Add(24 * time.Hour).Unix(),
+		"iat":  now.Unix(),
 	})
 
 	signedToken, err := token.SignedString(key)
@@ -75,19 +144,21 @@ func createJWMT(userID string) (string, error) {
 
 func (this *Implementation) ValidateToken(ctx context.Context, token *pb.Token) (*pb.User, error) {
 	key := []byte(os.Getenv("SIGNING_KEY"))
-	userID, err := validateJWT(token.Jwt, key)
+	userID, role, err := validateJWT(token.Jwt, key)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.User{
 		UserId: userID,
+		Role:   role,
 	}, nil
 }
 
-func validateJWT(tokenString string, signingKey []byte) (string, error) {
+func validateJWT(tokenString string, signingKey []byte) (string, string, error) {
 
 	type MyClaims struct {
 		jwt.RegisteredClaims
+		Role string `json:"role"`
 	}
 
 	parseToken, err := jwt.ParseWithClaims(tokenString, &MyClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -95,17 +166,23 @@ func validateJWT(tokenString string, signingKey []byte) (string, error) {
 	})
 	if err != nil {
 		if errors.Is(err, jwt.ErrSignatureInvalid) {
-			return "", status.Errorf(codes.Unauthenticated, "invalid token signature")
+			return "", "", status.Errorf(codes.Unauthenticated, "invalid token signature")
 		} else {
-			return "", status.Errorf(codes.Internal, "failed to parse token: %v", err)
+			return "", "", status.Errorf(codes.Internal, "failed to parse token: %v", err)
 		}
 	}
 
 	claims, ok := parseToken.Claims.(*MyClaims)
 	if !ok || !parseToken.Valid {
-		return "", status.Errorf(codes.Unauthenticated, "invalid token claims")
+		return "", "", status.Errorf(codes.Unauthenticated, "invalid token claims")
+	}
+
+	// Tokens issued before roles existed belong to customers
+	role := claims.Role
+	if role == "" {
+		role = roleCustomer
 	}
 
-	return claims.RegisteredClaims.Subject, nil
+	return claims.RegisteredClaims.Subject, role, nil
 
 }
@@ -16,8 +16,13 @@ const topic = "email"
 var wg sync.WaitGroup
 
 type EmailMsg struct {
-	OrderId string `json:"order_id"`
-	UserId  string `json:"user_id"`
+	OrderId  string `json:"order_id"`
+	UserId   string `json:"user_id"`
+	Type     string `json:"type"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Exponent int    `json:"exponent"`
+	Memo     string `json:"memo"`
 }
 
 func main() {
@@ -80,7 +85,25 @@ func handleMessage(msg *sarama.ConsumerMessage) {
 
 	fmt.Printf("Processing email for Order ID: %s, User ID: %s\n", emailMsg.OrderId, emailMsg.UserId)
 
-	err := email.Send(emailMsg.UserId, emailMsg.OrderId)
+	amount := email.FormatAmount(emailMsg.Amount, emailMsg.Currency, emailMsg.Exponent)
+
+	var err error
+	switch emailMsg.Type {
+	case "refund":
+		err = email.SendRefund(emailMsg.UserId, emailMsg.OrderId, amount)
+	case "expired":
+		err = email.SendExpired(emailMsg.UserId, emailMsg.OrderId, amount)
+	case "deposit":
+		err = email.SendDeposit(emailMsg.UserId, emailMsg.OrderId, amount)
+	case "withdrawal":
+		err = email.SendWithdrawal(emailMsg.UserId, emailMsg.OrderId, amount)
+	case "transfer_sent":
+		err = email.SendTransferSent(emailMsg.UserId, emailMsg.OrderId, amount, emailMsg.Memo)
+	case "transfer_received":
+		err = email.SendTransferReceived(emailMsg.UserId, emailMsg.OrderId, amount, emailMsg.Memo)
+	default:
+		err = email.Send(emailMsg.UserId, emailMsg.OrderId, amount)
+	}
 	if err != nil {
 		log.Printf("Failed to send email: %v", err)
 		return
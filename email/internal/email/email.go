@@ -6,14 +6,96 @@ import (
 	"os"
 )
 
-func Send(target string, orderID string) error {
-	senderEmail := os.Getenv("SENDER_EMAIL")
-	password := os.Getenv("EMAIL_PASSWORD")
-	recipientEmail := target
+// FormatAmount renders an amount in minor units as a decimal amount in its
+// currency, e.g. 12345 USD with exponent 2 becomes "123.45 USD".
+func FormatAmount(amount int64, currency string, exponent int) string {
+	if exponent <= 0 {
+		return fmt.Sprintf("%d %s", amount, currency)
+	}
 
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	divisor := int64(1)
+	for i := 0; i < exponent; i++ {
+		divisor *= 10
+	}
+
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/divisor, exponent, amount%divisor, currency)
+}
+
+func Send(target string, orderID string, amount string) error {
 	message := []byte("Subject: Order Confirmation\n" +
 		"\nYour order has been confirmed.\n" +
-		"Order ID: " + orderID + "\n")
+		"Order ID: " + orderID + "\n" +
+		"Amount: " + amount + "\n")
+
+	return send(target, orderID, message)
+}
+
+func SendRefund(target string, orderID string, amount string) error {
+	message := []byte("Subject: Refund Issued\n" +
+		"\nA refund has been issued for your order.\n" +
+		"Order ID: " + orderID + "\n" +
+		"Amount: " + amount + "\n")
+
+	return send(target, orderID, message)
+}
+
+func SendExpired(target string, orderID string, amount string) error {
+	message := []byte("Subject: Authorization Expired\n" +
+		"\nYour payment authorization expired and the held funds were released.\n" +
+		"Order ID: " + orderID + "\n" +
+		"Amount: " + amount + "\n")
+
+	return send(target, orderID, message)
+}
+
+func SendDeposit(target string, fundingID string, amount string) error {
+	message := []byte("Subject: Deposit Received\n" +
+		"\nYour deposit has been credited to your wallet.\n" +
+		"Reference: " + fundingID + "\n" +
+		"Amount: " + amount + "\n")
+
+	return send(target, fundingID, message)
+}
+
+func SendWithdrawal(target string, fundingID string, amount string) error {
+	message := []byte("Subject: Withdrawal Sent\n" +
+		"\nYour withdrawal has been sent to your bank.\n" +
+		"Reference: " + fundingID + "\n" +
+		"Amount: " + amount + "\n")
+
+	return send(target, fundingID, message)
+}
+
+func SendTransferSent(target string, transferID string, amount string, memo string) error {
+	message := []byte("Subject: Transfer Sent\n" +
+		"\nYour transfer has been sent.\n" +
+		"Reference: " + transferID + "\n" +
+		"Amount: " + amount + "\n" +
+		"Memo: " + memo + "\n")
+
+	return send(target, transferID, message)
+}
+
+func SendTransferReceived(target string, transferID string, amount string, memo string) error {
+	message := []byte("Subject: Transfer Received\n" +
+		"\nYou have received a transfer.\n" +
+		"Reference: " + transferID + "\n" +
+		"Amount: " + amount + "\n" +
+		"Memo: " + memo + "\n")
+
+	return send(target, transferID, message)
+}
+
+func send(target string, orderID string, message []byte) error {
+	senderEmail := os.Getenv("SENDER_EMAIL")
+	password := os.Getenv("EMAIL_PASSWORD")
+	recipientEmail := target
 
 	stmpServer := "smtp.gmail.com"
 	smtpPort := "587"
@@ -28,8 +28,15 @@ type LedgerMsg struct {
 	OrderID   string `json:"order_id"`
 	UserID    string `json:"user_id"`
 	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
 	Operation string `json:"operation"`
 	Date      string `json:"date"`
+
+	MerchantUserID string `json:"merchant_user_id"`
+	Gross          int64  `json:"gross"`
+	Fee            int64  `json:"fee"`
+	Net            int64  `json:"net"`
+	FeeCurrency    string `json:"fee_currency"`
 }
 
 func main() {
@@ -115,7 +122,13 @@ func handleMessage(msg *sarama.ConsumerMessage) {
 
 	fmt.Printf("Processing email for Order ID: %s, User ID: %s\n", ledgerMsg.OrderID, ledgerMsg.UserID)
 
-	err := ledger.Insert(db, ledgerMsg.OrderID, ledgerMsg.UserID, ledgerMsg.Amount, ledgerMsg.Operation, ledgerMsg.Date)
+	err := ledger.Insert(db, ledgerMsg.OrderID, ledgerMsg.UserID, ledgerMsg.Amount, ledgerMsg.Currency, ledgerMsg.Operation, ledgerMsg.Date, ledger.Fee{
+		MerchantUserId: ledgerMsg.MerchantUserID,
+		Gross:          ledgerMsg.Gross,
+		Fee:            ledgerMsg.Fee,
+		Net:            ledgerMsg.Net,
+		Currency:       ledgerMsg.FeeCurrency,
+	})
 	if err != nil {
 		log.Printf("Failed to send email: %v", err)
 		return
@@ -11,6 +11,12 @@ CREATE TABLE `ledger` (
     order_id VARCHAR(255) NOT NULL,
     user_id VARCHAR(255) NOT NULL,
     amount INT NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
     operation VARCHAR(250) NOT NULL,
-    transaction_date VARCHAR(250) NOT NULL
+    transaction_date VARCHAR(250) NOT NULL,
+    merchant_user_id VARCHAR(255) NOT NULL DEFAULT '',
+    gross INT NOT NULL DEFAULT 0,
+    fee INT NOT NULL DEFAULT 0,
+    net INT NOT NULL DEFAULT 0,
+    fee_currency CHAR(3) NOT NULL DEFAULT ''
 );
\ No newline at end of file
@@ -5,14 +5,27 @@ import (
 	"fmt"
 )
 
-func Insert(db *sql.DB, orderID, userID string, amount int64, operation, date string) error {
-	query := "INSERT INTO ledger (order_id, user_id, amount, operation, date) VALUES (?, ?, ?, ?, ?)"
+// Fee is the merchant side of a capture: which merchant was credited, how
+// much, the fee taken from it and the net, all in Currency. A split payment
+// records one capture per merchant. For a refund it is what the merchant paid
+// back: the gross refunded, the fee returned to it and the net. It is zero for
+// other operations.
+type Fee struct {
+	MerchantUserId string
+	Gross          int64
+	Fee            int64
+	Net            int64
+	Currency       string
+}
+
+func Insert(db *sql.DB, orderID, userID string, amount int64, currency, operation, date string, fee Fee) error {
+	query := "INSERT INTO ledger (order_id, user_id, amount, currency, operation, transaction_date, merchant_user_id, gross, fee, net, fee_currency) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
 	stmt, err := db.Prepare(query)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
-	_, err = stmt.Exec(query, orderID, userID, amount, operation, date)
+	_, err = stmt.Exec(orderID, userID, amount, currency, operation, date, fee.MerchantUserId, fee.Gross, fee.Fee, fee.Net, fee.Currency)
 	if err != nil {
 		return fmt.Errorf("failed to insert ledger entry: %w", err)
 	}
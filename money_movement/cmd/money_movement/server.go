@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"time"
 
+	"github.com/MikePham0630/gomicro/internal/bank"
 	mm "github.com/MikePham0630/gomicro/internal/implementation"
+	"github.com/MikePham0630/gomicro/internal/producer"
+	"github.com/MikePham0630/gomicro/internal/risk"
 	pd "github.com/MikePham0630/gomicro/proto"
 	_ "github.com/go-sql-driver/mysql" // MySQL driver
 	"google.golang.org/grpc"
@@ -17,6 +23,12 @@ const (
 	dbUser     = "money_movement_user"
 	dbPassword = "Auth123"
 	dbName     = "money_movement"
+
+	defaultAuthorizationTTL           = 7 * 24 * time.Hour
+	defaultAuthorizationSweepInterval = time.Minute
+	defaultSettlementInterval         = 24 * time.Hour
+	defaultFundingStaleAfter          = 5 * time.Minute
+	defaultFundingSweepInterval       = time.Minute
 )
 
 var db *sql.DB
@@ -46,9 +58,46 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// grpc server setup
-	grpcServer := grpc.NewServer()
-	pd.RegisterMoneyMovementServiceServer(grpcServer, mm.NewMoneyMovementImplementation(db))
+	// Load the risk rules Authorize scores payments with; without a rules
+	// file every authorization is allowed
+	var riskEngine *risk.Engine
+	if path := os.Getenv("RISK_RULES_FILE"); path != "" {
+		riskEngine, err = risk.Load(path)
+		if err != nil {
+			log.Fatalf("Error loading risk rules from %s: %v", path, err)
+		}
+	}
+
+	// Authorizations can be raised until they expire
+	authorizationTTL := durationFromEnv("AUTHORIZATION_TTL", defaultAuthorizationTTL)
+
+	bankAdapter, err := bankAdapterFromEnv()
+	if err != nil {
+		log.Fatalf("Error configuring the bank adapter: %v", err)
+	}
+
+	// grpc server setup, every call must carry the identity of its caller
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(mm.CallerInterceptor))
+	mmImplementation := mm.NewMoneyMovementImplementation(db, bankAdapter, riskEngine, authorizationTTL)
+	pd.RegisterMoneyMovementServiceServer(grpcServer, mmImplementation)
+
+	// Release funds held by authorizations nobody captured in time
+	sweepInterval := durationFromEnv("AUTHORIZATION_SWEEP_INTERVAL", defaultAuthorizationSweepInterval)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mmImplementation.RunExpirySweeper(ctx, authorizationTTL, sweepInterval)
+
+	// Move captured funds from merchants' INCOMING to SETTLED accounts
+	settlementInterval := durationFromEnv("SETTLEMENT_INTERVAL", defaultSettlementInterval)
+	go mmImplementation.RunSettlementScheduler(ctx, settlementInterval)
+
+	// Finish fundings whose outcome was lost between the bank call and the database
+	fundingStaleAfter := durationFromEnv("FUNDING_STALE_AFTER", defaultFundingStaleAfter)
+	fundingSweepInterval := durationFromEnv("FUNDING_SWEEP_INTERVAL", defaultFundingSweepInterval)
+	go mmImplementation.RunFundingSweeper(ctx, fundingStaleAfter, fundingSweepInterval)
+
+	// Publish events written to the outbox by committed transactions
+	go producer.NewRelay(db).Run(ctx)
 
 	// Start the gRPC server
 	listener, err := net.Listen("tcp", ":7000")
@@ -61,3 +110,35 @@ func main() {
 	}
 
 }
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// bankAdapterFromEnv returns the adapter named by BANK_ADAPTER. Deployments
+// talk to the bank's API at BANK_API_URL; the in-memory fake, which accepts
+// every transfer, has to be asked for explicitly for local and test runs.
+func bankAdapterFromEnv() (bank.Adapter, error) {
+	switch adapter := os.Getenv("BANK_ADAPTER"); adapter {
+	case "", "http":
+		url := os.Getenv("BANK_API_URL")
+		if url == "" {
+			return nil, fmt.Errorf("BANK_API_URL is not set")
+		}
+		return bank.NewHTTP(url, os.Getenv("BANK_API_KEY")), nil
+	case "fake":
+		log.Println("Using the fake bank adapter, no money is moved")
+		return bank.NewFake(0), nil
+	default:
+		return nil, fmt.Errorf("unknown BANK_ADAPTER %q", adapter)
+	}
+}
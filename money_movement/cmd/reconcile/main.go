@@ -0,0 +1,154 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/reconcile"
+	_ "github.com/go-sql-driver/mysql" // MySQL driver
+)
+
+const (
+	dbDriver = "mysql"
+
+	defaultMoneyMovementDSN = "money_movement_user:Auth123@tcp(mysql-money-movement:3306)/money_movement"
+	defaultLedgerDSN        = "ledger_user:Auth123@tcp(mysql-ledger:3306)/ledger"
+)
+
+func main() {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	fromFlag := flag.String("from", yesterday, "first day to reconcile, YYYY-MM-DD")
+	toFlag := flag.String("to", yesterday, "last day to reconcile, YYYY-MM-DD")
+	format := flag.String("format", "json", "report format: json or csv")
+	out := flag.String("out", "", "file to write the report to, stdout if empty")
+	republish := flag.Bool("republish", false, "republish the ledger events of missing entries")
+	mmDSN := flag.String("mm-dsn", envOr("MONEY_MOVEMENT_DSN", defaultMoneyMovementDSN), "money_movement database DSN")
+	ledgerDSN := flag.String("ledger-dsn", envOr("LEDGER_DSN", defaultLedgerDSN), "ledger database DSN")
+	flag.Parse()
+
+	from, err := time.ParseInLocation("2006-01-02", *fromFlag, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid -from %q: %v", *fromFlag, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", *toFlag, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid -to %q: %v", *toFlag, err)
+	}
+	if to.Before(from) {
+		log.Fatalf("-to %s is before -from %s", *toFlag, *fromFlag)
+	}
+	if *format != "json" && *format != "csv" {
+		log.Fatalf("Unsupported format %q", *format)
+	}
+
+	mmDB, err := openDB(*mmDSN)
+	if err != nil {
+		log.Fatalf("Error connecting to the money_movement database: %v", err)
+	}
+	defer mmDB.Close()
+
+	ledgerDB, err := openDB(*ledgerDSN)
+	if err != nil {
+		log.Fatalf("Error connecting to the ledger database: %v", err)
+	}
+	defer ledgerDB.Close()
+
+	report, err := reconcile.Run(mmDB, ledgerDB, from, to)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+	log.Printf("Reconciled %d transactions against %d ledger entries from %s to %s: %d discrepancies", report.Transactions, report.LedgerEntries, report.From, report.To, len(report.Discrepancies))
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Error creating %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "csv" {
+		err = writeCSV(w, report)
+	} else {
+		err = writeJSON(w, report)
+	}
+	if err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+
+	if *republish {
+		count, err := reconcile.Republish(mmDB, ledgerDB, report.Discrepancies)
+		if err != nil {
+			log.Fatalf("Republishing missing events failed: %v", err)
+		}
+		log.Printf("Republished %d missing ledger events", count)
+	}
+}
+
+func openDB(dsn string) (*sql.DB, error) {
+	db, err := sql.Open(dbDriver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func writeJSON(w io.Writer, report *reconcile.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeCSV(w io.Writer, report *reconcile.Report) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"kind", "pid", "operation", "transaction_id", "expected_amount", "expected_currency", "ledger_id", "actual_amount", "actual_currency", "date"})
+	if err != nil {
+		return err
+	}
+
+	for _, d := range report.Discrepancies {
+		record := []string{d.Kind, d.Pid, d.Operation, "", "", "", "", "", "", ""}
+		if d.Expected != nil {
+			record[3] = strconv.FormatInt(d.Expected.Id, 10)
+			record[4] = strconv.FormatInt(d.Expected.Amount, 10)
+			record[5] = d.Expected.Currency
+			record[9] = d.Expected.Date
+		}
+		if d.Actual != nil {
+			record[6] = strconv.FormatInt(d.Actual.Id, 10)
+			record[7] = strconv.FormatInt(d.Actual.Amount, 10)
+			record[8] = d.Actual.Currency
+			record[9] = d.Actual.Date
+		}
+		err = cw.Write(record)
+		if err != nil {
+			return fmt.Errorf("failed to write %s entry for %s: %w", d.Kind, d.Pid, err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -10,6 +10,9 @@ CREATE TABLE `wallet` (
     `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
     `user_id` VARCHAR(255) NOT NULL UNIQUE,
     `wallet_type` VARCHAR(255) NOT NULL,
+    `status` VARCHAR(255) NOT NULL DEFAULT 'ACTIVE',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `closed_at` TIMESTAMP NULL,
     INDEX(`user_id`)
 );
 
@@ -17,13 +20,17 @@ CREATE TABLE `account` (
     `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
     `cents` INT NOT NULL DEFAULT 0,
     `account_type` VARCHAR(255) NOT NULL,
+    `currency` CHAR(3) NOT NULL DEFAULT 'USD',
     `wallet_id` INT NOT NULL,
-    FOREIGN KEY (`wallet_id`) REFERENCES wallet(`id`)
+    FOREIGN KEY (`wallet_id`) REFERENCES wallet(`id`),
+    UNIQUE (`wallet_id`, `account_type`, `currency`)
 );
 
 CREATE TABLE `transaction` (
     `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
-    `pid` VARCHAR(255) NOT NULL UNIQUE,
+    `pid` VARCHAR(255) NOT NULL,
+    `transaction_type` VARCHAR(255) NOT NULL,
+    `capture_id` VARCHAR(255),
     `src_user_id` VARCHAR(255) NOT NULL,
     `dst_user_id` VARCHAR(255) NOT NULL,
     `src_wallet_id` INT NOT NULL,
@@ -34,22 +41,313 @@ CREATE TABLE `transaction` (
     `dst_account_type` VARCHAR(255) NOT NULL,
     `final_dst_merchant_wallet_id` INT,
     `amount` INT NOT NULL,
-    INDEX(`pid`)
+    `currency` CHAR(3) NOT NULL,
+    `converted_amount` INT NOT NULL,
+    `converted_currency` CHAR(3) NOT NULL,
+    `fx_quote_id` VARCHAR(255),
+    `fx_rate` DECIMAL(24, 12),
+    `fx_mid_rate` DECIMAL(24, 12),
+    `fx_spread_amount` INT NOT NULL DEFAULT 0,
+    `fee_amount` INT NOT NULL DEFAULT 0,
+    `memo` VARCHAR(255) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX(`pid`),
+    INDEX(`transaction_type`, `created_at`),
+    INDEX(`src_user_id`, `created_at`),
+    INDEX(`dst_user_id`, `created_at`),
+    UNIQUE (`capture_id`, `final_dst_merchant_wallet_id`)
 );
 
+CREATE TABLE `idempotency_keys` (
+    `user_id` VARCHAR(255) NOT NULL,
+    `idempotency_key` VARCHAR(255) NOT NULL,
+    `method` VARCHAR(255) NOT NULL,
+    `fingerprint` CHAR(64) NOT NULL,
+    `response` BLOB NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`, `idempotency_key`, `method`)
+);
+
+CREATE TABLE `outbox` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `topic` VARCHAR(255) NOT NULL,
+    `msg_key` VARCHAR(255) NOT NULL,
+    `payload` BLOB NOT NULL,
+    `attempts` INT NOT NULL DEFAULT 0,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `sent_at` TIMESTAMP NULL,
+    INDEX(`sent_at`, `id`)
+);
+
+-- a single row; the outbox relay replica holding it publishes pending rows
+CREATE TABLE `outbox_relay_lease` (
+    `id` TINYINT NOT NULL PRIMARY KEY,
+    `holder` VARCHAR(255) NOT NULL DEFAULT '',
+    `expires_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO outbox_relay_lease (id) VALUES (1);
+
+CREATE TABLE `fx_rates` (
+    `base_currency` CHAR(3) NOT NULL,
+    `quote_currency` CHAR(3) NOT NULL,
+    `rate` DECIMAL(24, 12) NOT NULL,
+    `spread_bps` INT NOT NULL DEFAULT 0,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`base_currency`, `quote_currency`)
+);
+
+CREATE TABLE `fx_quotes` (
+    `quote_id` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `src_currency` CHAR(3) NOT NULL,
+    `dst_currency` CHAR(3) NOT NULL,
+    `src_cents` INT NOT NULL,
+    `dst_cents` INT NOT NULL,
+    `spread_cents` INT NOT NULL,
+    `rate` DECIMAL(24, 12) NOT NULL,
+    `mid_rate` DECIMAL(24, 12) NOT NULL,
+    `pid` VARCHAR(255) UNIQUE,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `expires_at` DATETIME NOT NULL
+);
+
+CREATE TABLE `payments` (
+    `pid` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `status` VARCHAR(255) NOT NULL,
+    `customer_user_id` VARCHAR(255) NOT NULL,
+    `merchant_user_id` VARCHAR(255) NOT NULL,
+    `customer_wallet_id` INT NOT NULL,
+    `merchant_wallet_id` INT NOT NULL,
+    `currency` CHAR(3) NOT NULL,
+    `merchant_currency` CHAR(3) NOT NULL,
+    `authorized_cents` INT NOT NULL,
+    `captured_cents` INT NOT NULL DEFAULT 0,
+    `merchant_captured_cents` INT NOT NULL DEFAULT 0,
+    `released_cents` INT NOT NULL DEFAULT 0,
+    `refunded_cents` INT NOT NULL DEFAULT 0,
+    `risk_decision` VARCHAR(255) NOT NULL DEFAULT 'ALLOW',
+    `risk_reasons` VARCHAR(1024) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX(`status`, `created_at`),
+    INDEX(`customer_user_id`)
+);
+
+CREATE TABLE `payment_legs` (
+    `pid` VARCHAR(255) NOT NULL,
+    `leg` INT NOT NULL,
+    `merchant_user_id` VARCHAR(255) NOT NULL,
+    `merchant_wallet_id` INT NOT NULL,
+    `cents` INT NOT NULL,
+    `refunded_cents` INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (`pid`, `leg`),
+    INDEX(`merchant_user_id`)
+);
+
+CREATE TABLE `fundings` (
+    `funding_id` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `funding_type` VARCHAR(255) NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `wallet_id` INT NOT NULL,
+    `cents` INT NOT NULL,
+    `currency` CHAR(3) NOT NULL,
+    `failure_reason` VARCHAR(255) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX(`status`, `created_at`),
+    INDEX(`user_id`)
+);
+
+CREATE TABLE `settlements` (
+    `settlement_id` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `merchant_user_id` VARCHAR(255) NOT NULL,
+    `merchant_wallet_id` INT NOT NULL,
+    `currency` CHAR(3) NOT NULL,
+    `cents` INT NOT NULL,
+    `cutoff` TIMESTAMP NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX(`merchant_user_id`, `created_at`)
+);
+
+CREATE TABLE `settlement_items` (
+    `settlement_id` VARCHAR(255) NOT NULL,
+    `transaction_id` INT NOT NULL UNIQUE,
+    `pid` VARCHAR(255) NOT NULL,
+    `transaction_type` VARCHAR(255) NOT NULL,
+    `cents` INT NOT NULL,
+    INDEX(`settlement_id`)
+);
+
+CREATE TABLE `fee_schedules` (
+    `merchant_wallet_id` INT NOT NULL,
+    `currency` CHAR(3) NOT NULL,
+    `percent_bps` INT NOT NULL DEFAULT 0,
+    `fixed_cents` INT NOT NULL DEFAULT 0,
+    `min_cents` INT NOT NULL DEFAULT 0,
+    `max_cents` INT NOT NULL DEFAULT 0,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`merchant_wallet_id`, `currency`)
+);
+
+CREATE TABLE `velocity_limits` (
+    `wallet_type` VARCHAR(255) NOT NULL DEFAULT '',
+    `wallet_id` INT NOT NULL DEFAULT 0,
+    `currency` CHAR(3) NOT NULL,
+    `max_single_cents` INT NOT NULL DEFAULT 0,
+    `max_daily_cents` INT NOT NULL DEFAULT 0,
+    `max_monthly_cents` INT NOT NULL DEFAULT 0,
+    `max_hourly_count` INT NOT NULL DEFAULT 0,
+    `max_daily_transfer_cents` INT NOT NULL DEFAULT 0,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`wallet_type`, `wallet_id`, `currency`)
+);
+
+CREATE TABLE `risk_decisions` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `pid` VARCHAR(255) NULL,
+    `customer_user_id` VARCHAR(255) NOT NULL,
+    `merchant_user_id` VARCHAR(255) NOT NULL,
+    `cents` INT NOT NULL,
+    `currency` CHAR(3) NOT NULL,
+    `decision` VARCHAR(255) NOT NULL,
+    `reasons` VARCHAR(1024) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX(`decision`, `created_at`)
+);
+
+CREATE TABLE `journal_entries` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `currency` CHAR(3) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE `journal_postings` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `entry_id` BIGINT NOT NULL,
+    `account_id` INT NOT NULL,
+    `direction` VARCHAR(6) NOT NULL,
+    `cents` INT NOT NULL,
+    FOREIGN KEY (`entry_id`) REFERENCES journal_entries(`id`),
+    INDEX(`entry_id`),
+    INDEX(`account_id`)
+);
+
+-- default spending controls for customer wallets
+INSERT INTO velocity_limits (wallet_type, currency, max_single_cents, max_daily_cents, max_monthly_cents, max_hourly_count, max_daily_transfer_cents) VALUES
+('CUSTOMER', 'USD', 500000, 1000000, 5000000, 20, 100000),
+('CUSTOMER', 'EUR', 500000, 1000000, 5000000, 20, 100000),
+('CUSTOMER', 'GBP', 500000, 1000000, 5000000, 20, 100000);
+
 -- merchant and customer wallets
 INSERT INTO wallet (id, user_id, wallet_type) VALUES
 (1, 'gomicro@gmail.com', 'CUSTOMER');
 INSERT INTO wallet (id, user_id, wallet_type) VALUES
 (2, 'merchant_id', 'MERCHANT');
+INSERT INTO wallet (id, user_id, wallet_type) VALUES
+(3, 'house', 'HOUSE');
 
 
 -- customer account
-INSERT INTO account (cents, account_type, wallet_id) VALUES
-(5000000, 'DEFAULT', 1);
-INSERT INTO account (cents, account_type, wallet_id) VALUES
-(0, 'PAYMENT', 1);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(5000000, 'DEFAULT', 'USD', 1);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'PAYMENT', 'USD', 1);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(5000000, 'DEFAULT', 'EUR', 1);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'PAYMENT', 'EUR', 1);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(5000000, 'DEFAULT', 'GBP', 1);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'PAYMENT', 'GBP', 1);
 
 -- merchant account
-INSERT INTO account (cents, account_type, wallet_id) VALUES
-(0, 'INCOMING', 2);
\ No newline at end of file
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'INCOMING', 'USD', 2);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'INCOMING', 'EUR', 2);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'INCOMING', 'GBP', 2);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'SETTLED', 'USD', 2);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'SETTLED', 'EUR', 2);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'SETTLED', 'GBP', 2);
+
+-- house accounts: FX liquidity and spread revenue
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(100000000, 'FX', 'USD', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(100000000, 'FX', 'EUR', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(100000000, 'FX', 'GBP', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'FX_REVENUE', 'USD', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'FX_REVENUE', 'EUR', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'FX_REVENUE', 'GBP', 3);
+
+-- house accounts: money held at the bank for deposits and withdrawals awaiting payout
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(100000000, 'EXTERNAL_FUNDING', 'USD', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(100000000, 'EXTERNAL_FUNDING', 'EUR', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(100000000, 'EXTERNAL_FUNDING', 'GBP', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'PAYOUT_CLEARING', 'USD', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'PAYOUT_CLEARING', 'EUR', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'PAYOUT_CLEARING', 'GBP', 3);
+
+-- every other supported currency can be deposited and withdrawn as well;
+-- EXTERNAL_FUNDING goes negative as deposits come in
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'EXTERNAL_FUNDING', 'AUD', 3),
+(0, 'PAYOUT_CLEARING', 'AUD', 3),
+(0, 'EXTERNAL_FUNDING', 'BHD', 3),
+(0, 'PAYOUT_CLEARING', 'BHD', 3),
+(0, 'EXTERNAL_FUNDING', 'CAD', 3),
+(0, 'PAYOUT_CLEARING', 'CAD', 3),
+(0, 'EXTERNAL_FUNDING', 'CHF', 3),
+(0, 'PAYOUT_CLEARING', 'CHF', 3),
+(0, 'EXTERNAL_FUNDING', 'CNY', 3),
+(0, 'PAYOUT_CLEARING', 'CNY', 3),
+(0, 'EXTERNAL_FUNDING', 'HKD', 3),
+(0, 'PAYOUT_CLEARING', 'HKD', 3),
+(0, 'EXTERNAL_FUNDING', 'JPY', 3),
+(0, 'PAYOUT_CLEARING', 'JPY', 3),
+(0, 'EXTERNAL_FUNDING', 'KRW', 3),
+(0, 'PAYOUT_CLEARING', 'KRW', 3),
+(0, 'EXTERNAL_FUNDING', 'KWD', 3),
+(0, 'PAYOUT_CLEARING', 'KWD', 3),
+(0, 'EXTERNAL_FUNDING', 'NZD', 3),
+(0, 'PAYOUT_CLEARING', 'NZD', 3),
+(0, 'EXTERNAL_FUNDING', 'SEK', 3),
+(0, 'PAYOUT_CLEARING', 'SEK', 3),
+(0, 'EXTERNAL_FUNDING', 'SGD', 3),
+(0, 'PAYOUT_CLEARING', 'SGD', 3),
+(0, 'EXTERNAL_FUNDING', 'VND', 3),
+(0, 'PAYOUT_CLEARING', 'VND', 3);
+
+-- house accounts: merchant fee revenue
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'REVENUE', 'USD', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'REVENUE', 'EUR', 3);
+INSERT INTO account (cents, account_type, currency, wallet_id) VALUES
+(0, 'REVENUE', 'GBP', 3);
+
+-- house accounts: the other side of the opening balances above
+INSERT INTO account (cents, account_type, currency, wallet_id)
+SELECT -SUM(cents), 'OPENING_EQUITY', currency, 3 FROM account GROUP BY currency;
+
+-- opening journal entries, one per currency, so every balance can be rebuilt from postings
+INSERT INTO journal_entries (id, currency) VALUES (1, 'USD'), (2, 'EUR'), (3, 'GBP');
+INSERT INTO journal_postings (entry_id, account_id, direction, cents)
+SELECT e.id, a.id, IF(a.cents < 0, 'DEBIT', 'CREDIT'), ABS(a.cents)
+FROM account a JOIN journal_entries e ON e.currency = a.currency WHERE a.cents <> 0;
@@ -0,0 +1,32 @@
+// Package bank is the boundary between money_movement and the banks that move
+// money in and out of the platform.
+package bank
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDeclined is returned when the bank refuses a transfer, e.g. because of
+// insufficient funds on the user's bank account.
+var ErrDeclined = errors.New("transfer declined by bank")
+
+type Transfer struct {
+	Reference string // funding id, lets the bank deduplicate retries
+	UserId    string
+	Cents     int64
+	Currency  string // ISO 4217 code
+}
+
+// Adapter moves money between a user's bank account and the platform. A nil
+// error means the bank has accepted the transfer and ErrDeclined (possibly
+// wrapped) that it has refused it; only the latter fails the funding. Any other
+// error leaves the outcome unknown, so the funding is sent again later with the
+// same Reference and implementations must not move the money a second time for
+// it.
+type Adapter interface {
+	// Collect pulls money from the user's bank account into the platform.
+	Collect(ctx context.Context, transfer Transfer) error
+	// Payout pushes money from the platform to the user's bank account.
+	Payout(ctx context.Context, transfer Transfer) error
+}
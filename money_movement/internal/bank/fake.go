@@ -0,0 +1,72 @@
+package bank
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake is an in-memory Adapter for local development and tests. It accepts
+// every transfer up to Limit cents (no limit when zero) and records it once per
+// Reference.
+type Fake struct {
+	Limit int64
+
+	mu        sync.Mutex
+	collected []Transfer
+	paidOut   []Transfer
+}
+
+func NewFake(limit int64) *Fake {
+	return &Fake{
+		Limit: limit,
+	}
+}
+
+func (f *Fake) Collect(ctx context.Context, transfer Transfer) error {
+	if f.Limit > 0 && transfer.Cents > f.Limit {
+		return ErrDeclined
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !containsReference(f.collected, transfer.Reference) {
+		f.collected = append(f.collected, transfer)
+	}
+	return nil
+}
+
+func (f *Fake) Payout(ctx context.Context, transfer Transfer) error {
+	if f.Limit > 0 && transfer.Cents > f.Limit {
+		return ErrDeclined
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !containsReference(f.paidOut, transfer.Reference) {
+		f.paidOut = append(f.paidOut, transfer)
+	}
+	return nil
+}
+
+// Collected returns the transfers accepted by Collect so far.
+func (f *Fake) Collected() []Transfer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Transfer(nil), f.collected...)
+}
+
+// PaidOut returns the transfers accepted by Payout so far.
+func (f *Fake) PaidOut() []Transfer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Transfer(nil), f.paidOut...)
+}
+
+func containsReference(transfers []Transfer, reference string) bool {
+	for _, t := range transfers {
+		if t.Reference == reference {
+			return true
+		}
+	}
+	return false
+}
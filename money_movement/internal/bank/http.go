@@ -0,0 +1,86 @@
+package bank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultHTTPTimeout = 30 * time.Second
+
+// HTTP is the Adapter for a bank's transfer API. Transfers are POSTed as JSON
+// to /collections and /payouts under BaseURL, with the transfer reference as
+// the Idempotency-Key so that a retried funding is moved only once.
+type HTTP struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+func NewHTTP(baseURL, apiKey string) *HTTP {
+	return &HTTP{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: defaultHTTPTimeout},
+	}
+}
+
+type httpTransfer struct {
+	Reference string `json:"reference"`
+	UserId    string `json:"user_id"`
+	Cents     int64  `json:"cents"`
+	Currency  string `json:"currency"`
+}
+
+func (h *HTTP) Collect(ctx context.Context, transfer Transfer) error {
+	return h.post(ctx, "/collections", transfer)
+}
+
+func (h *HTTP) Payout(ctx context.Context, transfer Transfer) error {
+	return h.post(ctx, "/payouts", transfer)
+}
+
+// post sends transfer to path. The bank answers 2xx when it accepts the
+// transfer and 402 or 422 when it declines it; anything else is an error.
+func (h *HTTP) post(ctx context.Context, path string, transfer Transfer) error {
+	body, err := json.Marshal(httpTransfer{
+		Reference: transfer.Reference,
+		UserId:    transfer.UserId,
+		Cents:     transfer.Cents,
+		Currency:  transfer.Currency,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer %s: %w", transfer.Reference, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request for transfer %s: %w", transfer.Reference, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", transfer.Reference)
+	if h.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.APIKey)
+	}
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send transfer %s: %w", transfer.Reference, err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return nil
+	case res.StatusCode == http.StatusPaymentRequired || res.StatusCode == http.StatusUnprocessableEntity:
+		return ErrDeclined
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("bank rejected transfer %s with %s: %s", transfer.Reference, res.Status, strings.TrimSpace(string(msg)))
+	}
+}
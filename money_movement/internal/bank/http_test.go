@@ -0,0 +1,65 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPAdapter(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		payout  bool
+		wantErr error
+	}{
+		{name: "accepted collection", status: http.StatusCreated},
+		{name: "accepted payout", status: http.StatusOK, payout: true},
+		{name: "declined", status: http.StatusPaymentRequired, wantErr: ErrDeclined},
+		{name: "bank error", status: http.StatusInternalServerError, wantErr: errors.New("any")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, idempotencyKey, authorization string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				idempotencyKey = r.Header.Get("Idempotency-Key")
+				authorization = r.Header.Get("Authorization")
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			adapter := NewHTTP(srv.URL+"/v1/", "secret")
+			transfer := Transfer{Reference: "funding-1", UserId: "user", Cents: 100, Currency: "USD"}
+			var err error
+			wantPath := "/v1/collections"
+			if tt.payout {
+				err = adapter.Payout(context.Background(), transfer)
+				wantPath = "/v1/payouts"
+			} else {
+				err = adapter.Collect(context.Background(), transfer)
+			}
+
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("got error %v, want none", err)
+			case tt.wantErr == ErrDeclined && !errors.Is(err, ErrDeclined):
+				t.Fatalf("got error %v, want %v", err, ErrDeclined)
+			case tt.wantErr != nil && err == nil:
+				t.Fatal("got no error")
+			}
+			if path != wantPath {
+				t.Errorf("posted to %s, want %s", path, wantPath)
+			}
+			if idempotencyKey != transfer.Reference {
+				t.Errorf("Idempotency-Key = %q, want %q", idempotencyKey, transfer.Reference)
+			}
+			if authorization != "Bearer secret" {
+				t.Errorf("Authorization = %q", authorization)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package currency holds the ISO 4217 currencies supported by money_movement.
+package currency
+
+import "fmt"
+
+// exponents maps each supported ISO 4217 code to its minor-unit exponent, i.e.
+// the number of decimals between the amount stored in cents and the major unit.
+var exponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"NZD": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"USD": 2,
+	"VND": 0,
+}
+
+// Exponent returns the minor-unit exponent of code, or false if code is not a
+// supported ISO 4217 currency.
+func Exponent(code string) (int, bool) {
+	exponent, ok := exponents[code]
+	return exponent, ok
+}
+
+// Validate returns an error if code is not a supported ISO 4217 currency.
+func Validate(code string) error {
+	if _, ok := exponents[code]; !ok {
+		return fmt.Errorf("unsupported currency %q", code)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const selectWalletAccountsQuery = "SELECT id, cents, account_type, currency, wallet_id FROM accounts WHERE wallet_id = ? ORDER BY account_type, currency"
+
+func (impl *Implementation) GetBalances(ctx context.Context, getBalancesPayload *pb.GetBalancesPayload) (*pb.Balances, error) {
+	if err := callerFromContext(ctx).requireOwner(getBalancesPayload.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	//Begin a transaction so the wallet and its accounts are read consistently
+	tx, err := impl.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	w, err := fetchWallet(tx, getBalancesPayload.GetUserId())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	accounts, err := fetchWalletAccounts(tx, w.ID)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	balances := &pb.Balances{
+		UserId:     w.UserId,
+		WalletId:   w.ID,
+		WalletType: w.walletType,
+	}
+	for _, a := range accounts {
+		balances.Accounts = append(balances.Accounts, accountBalance(a))
+	}
+
+	return balances, nil
+}
+
+// accountBalance splits an account's cents into what the owner can spend and
+// what is reserved. PAYMENT accounts only ever hold funds of open
+// authorizations, so their whole balance is held.
+func accountBalance(a account) *pb.AccountBalance {
+	balance := &pb.AccountBalance{
+		AccountType: a.accountType,
+		Currency:    a.currency,
+	}
+	if a.accountType == "PAYMENT" {
+		balance.HeldCents = a.cents
+	} else {
+		balance.AvailableCents = a.cents
+	}
+	return balance
+}
+
+func fetchWalletAccounts(tx *sql.Tx, walletId int32) ([]account, error) {
+	stmt, err := tx.Prepare(selectWalletAccountsQuery)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	return queryAccounts(stmt, walletId)
+}
+
+func queryAccounts(stmt *sql.Stmt, walletId int32) ([]account, error) {
+	rows, err := stmt.Query(walletId)
+	if err != nil {
+		return nil, dbError("failed to query accounts", err)
+	}
+	defer rows.Close()
+
+	var accounts []account
+	for rows.Next() {
+		var a account
+		err := rows.Scan(&a.ID, &a.cents, &a.accountType, &a.currency, &a.walletID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan account: %v", err)
+		}
+		accounts = append(accounts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query accounts: %v", err)
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,284 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MikePham0630/gomicro/internal/bank"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requireSettled fails the test unless err is one of the outcomes a
+// concurrent operation may end with: success, or a rejection that leaves no
+// trace. A conflict that outlived its retries counts as a failure.
+func requireSettled(t *testing.T, op string, err error) {
+	t.Helper()
+	switch status.Code(err) {
+	case codes.OK, codes.FailedPrecondition, codes.ResourceExhausted:
+	default:
+		t.Errorf("%s: %v", op, err)
+	}
+}
+
+// watchBalances checks every committed state until stop is closed: no account
+// may be overdrawn and the total must stay at total. It returns the number of
+// violations seen.
+func watchBalances(e *testEnv, total int64, stop <-chan struct{}) <-chan int {
+	violations := make(chan int, 1)
+	go func() {
+		seen := 0
+		for {
+			select {
+			case <-stop:
+				violations <- seen
+				return
+			default:
+			}
+			var sum int64
+			for _, cents := range e.snapshot() {
+				if cents < 0 {
+					seen++
+				}
+				sum += cents
+			}
+			if sum != total {
+				seen++
+			}
+		}
+	}()
+	return violations
+}
+
+// TestConcurrentAuthorizationsAndTransfersConserveCents runs authorizations
+// in parallel with crossed transfers out of the same DEFAULT account through
+// the store interface.
+func TestConcurrentAuthorizationsAndTransfersConserveCents(t *testing.T) {
+	const (
+		workers = 20
+		rounds  = 25
+		funds   = 1000
+	)
+	e := newTestEnv(t, funds)
+	other := e.store.addWallet("other", walletTypeCustomer)
+	e.store.addAccount(other.ID, "DEFAULT", "USD", funds)
+
+	stop := make(chan struct{})
+	violations := watchBalances(e, 2*funds, stop)
+
+	// Half of the workers move money from the customer to other, the other
+	// half the opposite way, and every worker also authorizes a payment from
+	// the customer
+	var authorized atomic.Int64
+	var wg sync.WaitGroup
+	for i := range workers {
+		from, to := e.customer, other
+		if i%2 == 1 {
+			from, to = other, e.customer
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := asCaller(e.customer.UserId, roleCustomer)
+			for range rounds {
+				_, err := retryOnConflict(ctx, func() (struct{}, error) {
+					return struct{}{}, e.transferDefault(from, to, 7)
+				})
+				requireSettled(t, "transfer", err)
+
+				_, err = e.impl.Authorize(ctx, &pb.AuthorizePayload{
+					CustomerWalletUserId: e.customer.UserId,
+					MerchantWalletUserId: e.merchant.UserId,
+					Cents:                3,
+					Currency:             "USD",
+				})
+				requireSettled(t, "Authorize", err)
+				if err == nil {
+					authorized.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+
+	if n := <-violations; n > 0 {
+		t.Errorf("saw %d states with an overdrawn account or a changed total", n)
+	}
+	if authorized.Load() == 0 {
+		t.Error("no authorization succeeded")
+	}
+	if got := e.balance(e.customer, "PAYMENT", "USD"); got != authorized.Load()*3 {
+		t.Errorf("PAYMENT = %d, want %d", got, authorized.Load()*3)
+	}
+	if got := e.balance(e.customer, "DEFAULT", "USD") + e.balance(other, "DEFAULT", "USD"); got != 2*funds-authorized.Load()*3 {
+		t.Errorf("DEFAULT accounts hold %d, want %d", got, 2*funds-authorized.Load()*3)
+	}
+}
+
+// transferDefault moves cents between the DEFAULT accounts of two wallets in
+// one unit of work.
+func (e *testEnv) transferDefault(from, to wallet, cents int64) error {
+	tx, err := e.store.begin(context.Background())
+	if err != nil {
+		return err
+	}
+	src, err := tx.fetchAccount(from.ID, "DEFAULT", "USD")
+	if err == nil {
+		var dst account
+		dst, err = tx.fetchAccount(to.ID, "DEFAULT", "USD")
+		if err == nil {
+			err = tx.transfer(src, dst, cents)
+		}
+	}
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func TestRetryOnConflictRetriesDeadlocks(t *testing.T) {
+	deadlock := dbError("failed to update account", &mysql.MySQLError{Number: mysqlDeadlock})
+
+	attempts := 0
+	got, err := retryOnConflict(context.Background(), func() (int, error) {
+		attempts++
+		if attempts < maxConflictRetries {
+			return 0, deadlock
+		}
+		return 42, nil
+	})
+	if err != nil || got != 42 {
+		t.Fatalf("got %d, %v after %d attempts, want 42", got, err, attempts)
+	}
+
+	attempts = 0
+	_, err = retryOnConflict(context.Background(), func() (int, error) {
+		attempts++
+		return 0, deadlock
+	})
+	requireCode(t, err, codes.Aborted)
+	if attempts != maxConflictRetries {
+		t.Errorf("gave up after %d attempts, want %d", attempts, maxConflictRetries)
+	}
+
+	attempts = 0
+	_, err = retryOnConflict(context.Background(), func() (int, error) {
+		attempts++
+		return 0, status.Errorf(codes.FailedPrecondition, "insufficient funds")
+	})
+	requireCode(t, err, codes.FailedPrecondition)
+	if attempts != 1 {
+		t.Errorf("retried a non-conflict error %d times", attempts-1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	_, err = retryOnConflict(ctx, func() (int, error) {
+		attempts++
+		cancel()
+		return 0, deadlock
+	})
+	requireCode(t, err, codes.Canceled)
+	if attempts != 1 {
+		t.Errorf("retried %d times after the request was canceled", attempts-1)
+	}
+}
+
+// TestMySQLCrossedTransfersConserveCents runs crossed P2P transfers and
+// authorizations against MySQL, where row locks, their ordering and deadlock
+// retries are real; the in-memory store serializes units of work. It needs
+// MONEY_MOVEMENT_TEST_DSN to point at a database with the money_movement
+// schema.
+func TestMySQLCrossedTransfersConserveCents(t *testing.T) {
+	dsn := os.Getenv("MONEY_MOVEMENT_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MONEY_MOVEMENT_TEST_DSN is not set")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	const (
+		workers = 8
+		rounds  = 5
+		funds   = 10000
+	)
+	impl := NewMoneyMovementImplementation(db, bank.NewFake(0), nil, 0)
+	admin := asCaller("admin", roleAdmin)
+
+	suffix := uuid.NewString()
+	a, b, merchant := "a-"+suffix, "b-"+suffix, "m-"+suffix
+	var walletIds []int32
+	for _, w := range []struct{ userId, walletType string }{{a, walletTypeCustomer}, {b, walletTypeCustomer}, {merchant, walletTypeMerchant}} {
+		created, err := impl.CreateWallet(admin, &pb.CreateWalletPayload{UserId: w.userId, WalletType: w.walletType})
+		if err != nil {
+			t.Fatalf("CreateWallet %s: %v", w.userId, err)
+		}
+		walletIds = append(walletIds, created.WalletId)
+	}
+	for _, userId := range []string{a, b} {
+		_, err := impl.Deposit(admin, &pb.FundingPayload{UserId: userId, Cents: funds, Currency: "USD"})
+		if err != nil {
+			t.Fatalf("Deposit %s: %v", userId, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range workers {
+		from, to := a, b
+		if i%2 == 1 {
+			from, to = b, a
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := asCaller(from, roleCustomer)
+			for range rounds {
+				_, err := impl.Transfer(ctx, &pb.TransferPayload{FromUserId: from, ToUserId: to, Cents: 13, Currency: "USD"})
+				requireSettled(t, "Transfer", err)
+				_, err = impl.Authorize(ctx, &pb.AuthorizePayload{CustomerWalletUserId: from, MerchantWalletUserId: merchant, Cents: 11, Currency: "USD"})
+				requireSettled(t, "Authorize", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var total, overdrawn int64
+	for _, walletId := range walletIds {
+		rows, err := db.Query("SELECT cents FROM accounts WHERE wallet_id = ?", walletId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			var cents int64
+			if err := rows.Scan(&cents); err != nil {
+				t.Fatal(err)
+			}
+			if cents < 0 {
+				overdrawn++
+			}
+			total += cents
+		}
+		rows.Close()
+	}
+	if overdrawn > 0 {
+		t.Errorf("%d accounts are overdrawn", overdrawn)
+	}
+	if total != 2*funds {
+		t.Errorf("wallets hold %d, want %d", total, 2*funds)
+	}
+}
@@ -0,0 +1,55 @@
+package mm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	mysqlDuplicateEntry   = 1062
+	mysqlLockWaitTimeout  = 1205
+	mysqlDeadlock         = 1213
+	maxConflictRetries    = 3
+	conflictRetryBaseWait = 20 * time.Millisecond
+)
+
+// dbError converts a database error into a gRPC status. Deadlocks and lock
+// wait timeouts become codes.Aborted so the whole transaction can be retried.
+func dbError(msg string, err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && (mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout) {
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+// retryOnConflict runs fn again when it fails with codes.Aborted. fn must run
+// its own database transaction so that every attempt starts from scratch.
+// It stops waiting for the next attempt once ctx is done.
+func retryOnConflict[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var res T
+	var err error
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		if attempt > 0 {
+			wait := conflictRetryBaseWait << (attempt - 1)
+			timer := time.NewTimer(wait + rand.N(wait))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return res, status.FromContextError(ctx.Err()).Err()
+			case <-timer.C:
+			}
+		}
+		res, err = fn()
+		if status.Code(err) != codes.Aborted {
+			return res, err
+		}
+	}
+	return res, err
+}
@@ -0,0 +1,202 @@
+package mm
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/producer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// selectExpiredAuthorizationsQuery finds payments created before the cutoff
+// that still hold funds in the customer's PAYMENT account.
+const selectExpiredAuthorizationsQuery = "SELECT pid FROM payments WHERE status IN ('AUTHORIZED', 'PARTIALLY_CAPTURED') AND created_at < ?"
+
+// RunExpirySweeper expires stale authorizations every interval until ctx is done.
+func (impl *Implementation) RunExpirySweeper(ctx context.Context, ttl, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := impl.ExpireAuthorizations(time.Now().Add(-ttl))
+			if err != nil {
+				log.Printf("Failed to expire authorizations: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Expired %d authorizations", expired)
+			}
+		}
+	}
+}
+
+// ExpireAuthorizations returns the remaining funds of every authorization
+// created before cutoff to the customer's DEFAULT account. Untouched payments
+// become EXPIRED; partially captured ones are closed out as CAPTURED.
+func (impl *Implementation) ExpireAuthorizations(cutoff time.Time) (int, error) {
+	rows, err := impl.db.Query(selectExpiredAuthorizationsQuery, cutoff)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to query expired authorizations: %v", err)
+	}
+
+	var pids []string
+	for rows.Next() {
+		var pid string
+		if err := rows.Scan(&pid); err != nil {
+			rows.Close()
+			return 0, status.Errorf(codes.Internal, "failed to scan expired authorization: %v", err)
+		}
+		pids = append(pids, pid)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to query expired authorizations: %v", err)
+	}
+
+	expired := 0
+	for _, pid := range pids {
+		_, err := retryOnConflict(context.Background(), func() (struct{}, error) {
+			return struct{}{}, impl.expireAuthorization(pid)
+		})
+		if err != nil {
+			log.Printf("Failed to expire authorization %s: %v", pid, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (impl *Implementation) expireAuthorization(pid string) error {
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	p, err := fetchPayment(tx, pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	// The payment may have been captured or voided since it was selected
+	nextStatus := paymentStatusExpired
+	switch p.status {
+	case paymentStatusAuthorized:
+	case paymentStatusPartiallyCaptured:
+		nextStatus = p.capturedStatus()
+	default:
+		return tx.Rollback()
+	}
+
+	remaining := p.remaining()
+	err = p.transition(nextStatus)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	authorizeTransaction, err := fetchTransaction(tx, pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	srcAccount, err := fetchAccount(tx, authorizeTransaction.dstAccountWalletId, "PAYMENT", authorizeTransaction.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	dstAccount, err := fetchAccount(tx, authorizeTransaction.srcAccountWalletId, "DEFAULT", authorizeTransaction.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	err = transfer(tx, srcAccount, dstAccount, remaining)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	customerWallet, err := fetchWallet(tx, authorizeTransaction.srcUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	merchantWallet, err := fetchWalletWithWalletId(tx, authorizeTransaction.finalDstMerchantWalletId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	err = createTransaction(tx, authorizeTransaction.pid, transactionTypeExpire, srcAccount, dstAccount, customerWallet, customerWallet, merchantWallet, remaining)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	p.releasedCents += remaining
+	err = updatePayment(tx, p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	err = producer.EnqueueExpiryMessage(tx, authorizeTransaction.pid, authorizeTransaction.srcUserId, remaining, authorizeTransaction.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return status.Errorf(codes.Internal, "failed to enqueue expiry message: %v", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/MikePham0630/gomicro/internal/currency"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// revenueAccountType is the house account that collects merchant fees.
+const revenueAccountType = "REVENUE"
+
+const (
+	upsertFeeScheduleQuery = `INSERT INTO fee_schedules (merchant_wallet_id, currency, percent_bps, fixed_cents, min_cents, max_cents) VALUES (?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE percent_bps = VALUES(percent_bps), fixed_cents = VALUES(fixed_cents), min_cents = VALUES(min_cents), max_cents = VALUES(max_cents)`
+	selectFeeScheduleQuery = "SELECT percent_bps, fixed_cents, min_cents, max_cents FROM fee_schedules WHERE merchant_wallet_id = ? AND currency = ?"
+)
+
+// selectCapturedFeesQuery sums what one merchant of a payment was credited
+// and charged over all captures, and the fees returned to it over all refunds,
+// in the merchant's currency.
+const selectCapturedFeesQuery = `SELECT COALESCE(SUM(IF(transaction_type = 'CAPTURE', converted_amount, 0)), 0), COALESCE(SUM(IF(transaction_type = 'CAPTURE', fee_amount, 0)), 0), COALESCE(SUM(IF(transaction_type = 'REFUND', fee_amount, 0)), 0)
+	FROM transactions WHERE pid = ? AND transaction_type IN ('CAPTURE', 'REFUND') AND final_dst_merchant_wallet_id = ?`
+
+// feeSchedule is what a merchant pays per capture, in minor units of the
+// merchant's currency. A zero minCents or maxCents means no minimum or cap.
+type feeSchedule struct {
+	percentBps int64
+	fixedCents int64
+	minCents   int64
+	maxCents   int64
+}
+
+// fee returns the fee on gross. The percentage is rounded half up and the fee
+// never exceeds gross.
+func (s feeSchedule) fee(gross int64) int64 {
+	fee := (gross*s.percentBps+5000)/10000 + s.fixedCents
+	if s.minCents > 0 && fee < s.minCents {
+		fee = s.minCents
+	}
+	if s.maxCents > 0 && fee > s.maxCents {
+		fee = s.maxCents
+	}
+	if fee > gross {
+		fee = gross
+	}
+	return fee
+}
+
+func (impl *Implementation) SetFeeSchedule(ctx context.Context, feeSchedulePayload *pb.FeeSchedule) (*emptypb.Empty, error) {
+	if err := callerFromContext(ctx).requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	if err := currency.Validate(feeSchedulePayload.GetCurrency()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if feeSchedulePayload.GetPercentBps() < 0 || feeSchedulePayload.GetPercentBps() > 10000 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid fee percentage %d bps", feeSchedulePayload.GetPercentBps())
+	}
+	if feeSchedulePayload.GetFixedCents() < 0 || feeSchedulePayload.GetMinCents() < 0 || feeSchedulePayload.GetMaxCents() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "fee amounts must not be negative")
+	}
+	if feeSchedulePayload.GetMaxCents() > 0 && feeSchedulePayload.GetMinCents() > feeSchedulePayload.GetMaxCents() {
+		return nil, status.Errorf(codes.InvalidArgument, "minimum fee %d exceeds cap %d", feeSchedulePayload.GetMinCents(), feeSchedulePayload.GetMaxCents())
+	}
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	merchantWallet, err := fetchWallet(tx, feeSchedulePayload.GetMerchantUserId())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	if merchantWallet.walletType != walletTypeMerchant {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "wallet %d is not a merchant wallet", merchantWallet.ID)
+	}
+
+	stmt, err := tx.Prepare(upsertFeeScheduleQuery)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	_, err = stmt.Exec(merchantWallet.ID, feeSchedulePayload.GetCurrency(), feeSchedulePayload.GetPercentBps(), feeSchedulePayload.GetFixedCents(), feeSchedulePayload.GetMinCents(), feeSchedulePayload.GetMaxCents())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to store fee schedule: %v", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// fetchFeeSchedule returns the merchant's schedule for currencyCode. Merchants
+// without one pay no fee.
+func fetchFeeSchedule(tx *sql.Tx, merchantWalletId int32, currencyCode string) (feeSchedule, error) {
+	var s feeSchedule
+	stmt, err := tx.Prepare(selectFeeScheduleQuery)
+	if err != nil {
+		return s, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = stmt.QueryRow(merchantWalletId, currencyCode).Scan(&s.percentBps, &s.fixedCents, &s.minCents, &s.maxCents)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return feeSchedule{}, nil
+		}
+		return s, dbError("failed to query fee schedule", err)
+	}
+	return s, nil
+}
+
+// chargeFee moves fee from the merchant's INCOMING account to the house
+// REVENUE account of the same currency.
+func chargeFee(tx unitOfWork, merchantAccount account, fee int64) error {
+	if fee == 0 {
+		return nil
+	}
+
+	houseWallet, err := tx.fetchWallet(houseWalletUserId)
+	if err != nil {
+		return err
+	}
+
+	revenueAccount, err := tx.fetchAccount(houseWallet.ID, revenueAccountType, merchantAccount.currency)
+	if err != nil {
+		return err
+	}
+
+	return tx.transfer(merchantAccount, revenueAccount, fee)
+}
+
+// returnFee gives the merchant back, from the house REVENUE account, the share
+// of the fees it paid on pid that matches refunded cents on top of
+// alreadyRefunded, and returns it. The share is taken of the running total,
+// less what earlier refunds returned, so that refunding everything captured
+// returns the fees in full even when the payment was captured again between
+// refunds.
+func returnFee(tx unitOfWork, pid string, merchantAccount account, alreadyRefunded, refunded int64) (int64, error) {
+	gross, fee, alreadyReturned, err := tx.fetchCapturedFees(pid, merchantAccount.walletID)
+	if err != nil {
+		return 0, err
+	}
+	if gross == 0 {
+		return 0, nil
+	}
+
+	returned := fee*(alreadyRefunded+refunded)/gross - alreadyReturned
+	if returned <= 0 {
+		return 0, nil
+	}
+
+	houseWallet, err := tx.fetchWallet(houseWalletUserId)
+	if err != nil {
+		return 0, err
+	}
+
+	revenueAccount, err := tx.fetchAccount(houseWallet.ID, revenueAccountType, merchantAccount.currency)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.transfer(revenueAccount, merchantAccount, returned)
+	if err != nil {
+		return 0, err
+	}
+	return returned, nil
+}
+
+func fetchCapturedFees(tx *sql.Tx, pid string, merchantWalletId int32) (int64, int64, int64, error) {
+	stmt, err := tx.Prepare(selectCapturedFeesQuery)
+	if err != nil {
+		return 0, 0, 0, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	var gross, fee, returned int64
+	err = stmt.QueryRow(pid, merchantWalletId).Scan(&gross, &fee, &returned)
+	if err != nil {
+		return 0, 0, 0, dbError("failed to query captured fees", err)
+	}
+	return gross, fee, returned, nil
+}
@@ -0,0 +1,565 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MikePham0630/gomicro/internal/bank"
+	"github.com/MikePham0630/gomicro/internal/currency"
+	"github.com/MikePham0630/gomicro/internal/producer"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	fundingTypeDeposit    = "DEPOSIT"
+	fundingTypeWithdrawal = "WITHDRAWAL"
+
+	fundingStatusPending   = "PENDING"
+	fundingStatusCompleted = "COMPLETED"
+	fundingStatusFailed    = "FAILED"
+
+	// Deposits are paid out of the house EXTERNAL_FUNDING account, the contra
+	// side of money held at the bank, which may therefore go negative.
+	// Withdrawals wait in PAYOUT_CLEARING until the bank pays them out and then
+	// move back to EXTERNAL_FUNDING.
+	externalFundingAccountType = "EXTERNAL_FUNDING"
+	payoutClearingAccountType  = "PAYOUT_CLEARING"
+
+	// maxFailureReasonLength is the size of fundings.failure_reason
+	maxFailureReasonLength = 255
+)
+
+const (
+	insertFundingQuery = "INSERT INTO fundings (funding_id, funding_type, status, user_id, wallet_id, cents, currency) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	selectFundingQuery = "SELECT funding_id, funding_type, status, user_id, wallet_id, cents, currency, failure_reason FROM fundings WHERE funding_id = ?"
+	updateFundingQuery = "UPDATE fundings SET status = ?, failure_reason = ? WHERE funding_id = ?"
+)
+
+// selectStaleFundingsQuery finds fundings created before the cutoff that never
+// heard back from the bank.
+const selectStaleFundingsQuery = "SELECT funding_id, funding_type, status, user_id, wallet_id, cents, currency, failure_reason FROM fundings WHERE status = 'PENDING' AND created_at < ?"
+
+type funding struct {
+	fundingId     string
+	fundingType   string
+	status        string
+	userId        string
+	walletId      int32
+	cents         int64
+	currency      string
+	failureReason string
+}
+
+func (f funding) toProto() *pb.Funding {
+	return &pb.Funding{
+		FundingId:     f.fundingId,
+		Type:          f.fundingType,
+		Status:        f.status,
+		UserId:        f.userId,
+		Cents:         f.cents,
+		Currency:      f.currency,
+		FailureReason: f.failureReason,
+	}
+}
+
+// Deposit tops up the customer's DEFAULT account with money collected from
+// their bank.
+func (impl *Implementation) Deposit(ctx context.Context, fundingPayload *pb.FundingPayload) (*pb.Funding, error) {
+	return impl.fund(ctx, fundingTypeDeposit, fundingPayload)
+}
+
+// Withdraw pays money from the customer's DEFAULT account out to their bank.
+func (impl *Implementation) Withdraw(ctx context.Context, fundingPayload *pb.FundingPayload) (*pb.Funding, error) {
+	return impl.fund(ctx, fundingTypeWithdrawal, fundingPayload)
+}
+
+// fund records a PENDING funding and drives it through the bank.
+func (impl *Implementation) fund(ctx context.Context, fundingType string, fundingPayload *pb.FundingPayload) (*pb.Funding, error) {
+	if err := callerFromContext(ctx).requireOwner(fundingPayload.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if fundingPayload.GetCents() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
+	if err := currency.Validate(fundingPayload.GetCurrency()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	var replay bool
+	f, err := retryOnConflict(ctx, func() (funding, error) {
+		f, replayed, err := impl.startFunding(ctx, fundingType, fundingPayload)
+		replay = replayed
+		return f, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A retried request gets the funding as it stands now. One still PENDING,
+	// e.g. because the first attempt died before hearing from the bank, is
+	// driven again: the bank deduplicates it on the funding id.
+	if replay && f.status != fundingStatusPending {
+		return f.toProto(), nil
+	}
+
+	f, err = impl.driveFunding(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.toProto(), nil
+}
+
+// driveFunding hands a PENDING funding to the bank adapter outside of any
+// database transaction and completes or fails it with the outcome. The funding
+// id is the transfer reference, so driving a funding again never moves the
+// money twice.
+func (impl *Implementation) driveFunding(ctx context.Context, f funding) (funding, error) {
+	transfer := bank.Transfer{
+		Reference: f.fundingId,
+		UserId:    f.userId,
+		Cents:     f.cents,
+		Currency:  f.currency,
+	}
+	var bankErr error
+	if f.fundingType == fundingTypeDeposit {
+		bankErr = impl.bank.Collect(ctx, transfer)
+	} else {
+		bankErr = impl.bank.Payout(ctx, transfer)
+	}
+
+	// Only a decline fails the funding. Any other error, e.g. a timeout, does
+	// not tell whether the bank moved the money, so the funding stays PENDING
+	// for the sweeper to drive again.
+	if bankErr != nil && !errors.Is(bankErr, bank.ErrDeclined) {
+		log.Printf("Funding %s left pending: %v", f.fundingId, bankErr)
+		return f, nil
+	}
+
+	return retryOnConflict(ctx, func() (funding, error) {
+		return impl.completeFunding(f.fundingId, bankErr)
+	})
+}
+
+// RunFundingSweeper drives fundings left PENDING for longer than staleAfter
+// every interval until ctx is done.
+func (impl *Implementation) RunFundingSweeper(ctx context.Context, staleAfter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			redriven, err := impl.RedriveFundings(ctx, time.Now().Add(-staleAfter))
+			if err != nil {
+				log.Printf("Failed to redrive fundings: %v", err)
+				continue
+			}
+			if redriven > 0 {
+				log.Printf("Redrove %d stale fundings", redriven)
+			}
+		}
+	}
+}
+
+// RedriveFundings drives every funding still PENDING that was created before
+// cutoff through the bank again, so that a crash between the bank call and
+// completeFunding neither loses a deposit nor leaves a withdrawal stuck in
+// PAYOUT_CLEARING. It returns the number of fundings completed or failed;
+// those the bank still gives no answer for stay PENDING.
+func (impl *Implementation) RedriveFundings(ctx context.Context, cutoff time.Time) (int, error) {
+	rows, err := impl.db.QueryContext(ctx, selectStaleFundingsQuery, cutoff)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to query stale fundings: %v", err)
+	}
+
+	var fundings []funding
+	for rows.Next() {
+		var f funding
+		err := rows.Scan(&f.fundingId, &f.fundingType, &f.status, &f.userId, &f.walletId, &f.cents, &f.currency, &f.failureReason)
+		if err != nil {
+			rows.Close()
+			return 0, status.Errorf(codes.Internal, "failed to scan stale funding: %v", err)
+		}
+		fundings = append(fundings, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to query stale fundings: %v", err)
+	}
+
+	redriven := 0
+	for _, f := range fundings {
+		f, err := impl.driveFunding(ctx, f)
+		if err != nil {
+			log.Printf("Failed to redrive funding %s: %v", f.fundingId, err)
+			continue
+		}
+		if f.status != fundingStatusPending {
+			redriven++
+		}
+	}
+
+	return redriven, nil
+}
+
+// startFunding inserts the PENDING funding. Withdrawals move the amount to
+// PAYOUT_CLEARING right away so it cannot be spent while the bank pays it out.
+func (impl *Implementation) startFunding(ctx context.Context, fundingType string, fundingPayload *pb.FundingPayload) (funding, bool, error) {
+	var f funding
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return f, false, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	// Replay the stored funding if this request was already processed
+	idempotencyKey := idempotencyKeyFromContext(ctx)
+	var storedResponse pb.Funding
+	fingerprint, replay, err := lookupIdempotentResponse(tx, callerFromContext(ctx).userId, idempotencyKey, fundingType, fundingPayload, &storedResponse)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+	if replay {
+		f, err = fetchFunding(tx, storedResponse.GetFundingId())
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, false, err
+		}
+		return f, true, tx.Commit()
+	}
+
+	customerWallet, err := fetchWallet(tx, fundingPayload.GetUserId())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	if customerWallet.walletType != walletTypeCustomer || customerWallet.status != walletStatusActive {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, status.Errorf(codes.FailedPrecondition, "wallet %d cannot be funded", customerWallet.ID)
+	}
+
+	customerAccount, err := fetchAccount(tx, customerWallet.ID, "DEFAULT", fundingPayload.GetCurrency())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	// Resolve the house accounts completeFunding moves money through before
+	// the bank is called, so that a currency the house cannot fund fails the
+	// request here instead of after the bank has moved the money
+	houseWallet, err := fetchWallet(tx, houseWalletUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	_, err = fetchAccount(tx, houseWallet.ID, externalFundingAccountType, fundingPayload.GetCurrency())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	clearingAccount, err := fetchAccount(tx, houseWallet.ID, payoutClearingAccountType, fundingPayload.GetCurrency())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	f = funding{
+		fundingId:   uuid.NewString(),
+		fundingType: fundingType,
+		status:      fundingStatusPending,
+		userId:      customerWallet.UserId,
+		walletId:    customerWallet.ID,
+		cents:       fundingPayload.GetCents(),
+		currency:    fundingPayload.GetCurrency(),
+	}
+
+	if fundingType == fundingTypeWithdrawal {
+		err = transfer(tx, customerAccount, clearingAccount, f.cents)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, false, err
+		}
+
+		err = createTransaction(tx, f.fundingId, transactionTypeWithdrawal, customerAccount, clearingAccount, customerWallet, houseWallet, houseWallet, f.cents)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, false, err
+		}
+	}
+
+	err = insertFunding(tx, f)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	err = saveIdempotentResponse(tx, callerFromContext(ctx).userId, idempotencyKey, fundingType, fingerprint, f.toProto())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, false, err
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return f, false, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return f, false, nil
+}
+
+// completeFunding settles a PENDING funding with the result of the bank call,
+// which is nil or bank.ErrDeclined. A completed deposit credits the customer
+// from EXTERNAL_FUNDING; a withdrawal leaves PAYOUT_CLEARING for
+// EXTERNAL_FUNDING once paid out and goes back to the customer once declined.
+func (impl *Implementation) completeFunding(fundingId string, bankErr error) (funding, error) {
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return funding{}, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	f, err := fetchFunding(tx, fundingId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, err
+	}
+
+	if f.status != fundingStatusPending {
+		return f, tx.Rollback()
+	}
+
+	f.status = fundingStatusCompleted
+	if bankErr != nil {
+		f.status = fundingStatusFailed
+		f.failureReason = truncateFailureReason(bankErr.Error())
+	}
+
+	// A declined deposit never moved any money
+	if f.fundingType == fundingTypeWithdrawal || f.status == fundingStatusCompleted {
+		customerWallet, err := fetchWalletWithWalletId(tx, f.walletId)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, err
+		}
+
+		customerAccount, err := fetchAccount(tx, customerWallet.ID, "DEFAULT", f.currency)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, err
+		}
+
+		houseWallet, err := fetchWallet(tx, houseWalletUserId)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, err
+		}
+
+		srcAccountType, transactionType := externalFundingAccountType, transactionTypeDeposit
+		if f.fundingType == fundingTypeWithdrawal {
+			srcAccountType, transactionType = payoutClearingAccountType, transactionTypeWithdrawalReversal
+			if f.status == fundingStatusCompleted {
+				transactionType = transactionTypeWithdrawalPayout
+			}
+		}
+
+		srcAccount, err := fetchAccount(tx, houseWallet.ID, srcAccountType, f.currency)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, err
+		}
+
+		dstAccount, dstWallet := customerAccount, customerWallet
+		if transactionType == transactionTypeWithdrawalPayout {
+			dstAccount, err = fetchAccount(tx, houseWallet.ID, externalFundingAccountType, f.currency)
+			if err != nil {
+				rollbackErr := tx.Rollback()
+				if rollbackErr != nil {
+					return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+				}
+				return f, err
+			}
+			dstWallet = houseWallet
+		}
+
+		err = transfer(tx, srcAccount, dstAccount, f.cents)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, err
+		}
+
+		err = createTransaction(tx, f.fundingId, transactionType, srcAccount, dstAccount, houseWallet, dstWallet, dstWallet, f.cents)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, err
+		}
+	}
+
+	if f.status == fundingStatusCompleted {
+		if f.fundingType == fundingTypeDeposit {
+			err = producer.EnqueueDepositMessage(tx, f.fundingId, f.userId, f.cents, f.currency)
+		} else {
+			err = producer.EnqueueWithdrawalMessage(tx, f.fundingId, f.userId, f.cents, f.currency)
+		}
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return f, status.Errorf(codes.Internal, "failed to enqueue funding message: %v", err)
+		}
+	}
+
+	err = updateFunding(tx, f)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return f, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return f, err
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return f, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return f, nil
+}
+
+// truncateFailureReason cuts reason down to the size of
+// fundings.failure_reason without splitting a UTF-8 sequence.
+func truncateFailureReason(reason string) string {
+	if len(reason) <= maxFailureReasonLength {
+		return reason
+	}
+	end := maxFailureReasonLength
+	for end > 0 && !utf8.RuneStart(reason[end]) {
+		end--
+	}
+	return reason[:end]
+}
+
+func insertFunding(tx *sql.Tx, f funding) error {
+	stmt, err := tx.Prepare(insertFundingQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert funding statement: %v", err)
+	}
+
+	_, err = stmt.Exec(f.fundingId, f.fundingType, f.status, f.userId, f.walletId, f.cents, f.currency)
+	if err != nil {
+		return dbError("failed to insert funding", err)
+	}
+
+	return nil
+}
+
+// fetchFunding locks the funding row for the rest of tx.
+func fetchFunding(tx *sql.Tx, fundingId string) (funding, error) {
+	var f funding
+	stmt, err := tx.Prepare(selectFundingQuery + " FOR UPDATE")
+	if err != nil {
+		return f, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = scanFunding(stmt.QueryRow(fundingId), &f)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return f, status.Errorf(codes.NotFound, "funding not found: %s", fundingId)
+		}
+		return f, dbError("failed to query funding", err)
+	}
+	return f, nil
+}
+
+func updateFunding(tx *sql.Tx, f funding) error {
+	stmt, err := tx.Prepare(updateFundingQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare update funding statement: %v", err)
+	}
+
+	_, err = stmt.Exec(f.status, f.failureReason, f.fundingId)
+	if err != nil {
+		return dbError("failed to update funding", err)
+	}
+
+	return nil
+}
+
+func scanFunding(row *sql.Row, f *funding) error {
+	return row.Scan(&f.fundingId, &f.fundingType, &f.status, &f.userId, &f.walletId, &f.cents, &f.currency, &f.failureReason)
+}
@@ -0,0 +1,349 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/currency"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// fxQuoteTTL is how long a quoted rate stays valid for Authorize.
+	fxQuoteTTL = 2 * time.Minute
+
+	houseWalletUserId = "house"
+
+	upsertFxRateQuery  = "INSERT INTO fx_rates (base_currency, quote_currency, rate, spread_bps) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE rate = VALUES(rate), spread_bps = VALUES(spread_bps)"
+	selectFxRateQuery  = "SELECT rate, spread_bps FROM fx_rates WHERE base_currency = ? AND quote_currency = ?"
+	insertFxQuoteQuery = "INSERT INTO fx_quotes (quote_id, src_currency, dst_currency, src_cents, dst_cents, spread_cents, rate, mid_rate, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, DATE_ADD(NOW(), INTERVAL ? SECOND))"
+	selectFxQuoteQuery = "SELECT quote_id, src_currency, dst_currency, src_cents, dst_cents, rate, mid_rate, COALESCE(pid, ''), expires_at > NOW() FROM fx_quotes WHERE quote_id = ? FOR UPDATE"
+	useFxQuoteQuery    = "UPDATE fx_quotes SET pid = ? WHERE quote_id = ?"
+)
+
+type fxQuote struct {
+	quoteId     string
+	srcCurrency string
+	dstCurrency string
+	srcCents    int64
+	dstCents    int64
+	rate        string
+	midRate     string
+	pid         string
+	active      bool
+}
+
+// SetFxRates loads mid-market rates and the spread charged on top of them.
+// Rates are decimal strings in units of quote currency per unit of base currency.
+func (impl *Implementation) SetFxRates(ctx context.Context, setFxRatesPayload *pb.SetFxRatesPayload) (*emptypb.Empty, error) {
+	if err := callerFromContext(ctx).requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	for _, r := range setFxRatesPayload.GetRates() {
+		if err := validateCurrencyPair(r.GetBaseCurrency(), r.GetQuoteCurrency()); err != nil {
+			return nil, err
+		}
+		rate, ok := new(big.Rat).SetString(r.GetRate())
+		if !ok || rate.Sign() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid rate %q for %s/%s", r.GetRate(), r.GetBaseCurrency(), r.GetQuoteCurrency())
+		}
+		if r.GetSpreadBps() < 0 || r.GetSpreadBps() >= 10000 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid spread %d bps for %s/%s", r.GetSpreadBps(), r.GetBaseCurrency(), r.GetQuoteCurrency())
+		}
+	}
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	stmt, err := tx.Prepare(upsertFxRateQuery)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	for _, r := range setFxRatesPayload.GetRates() {
+		_, err = stmt.Exec(r.GetBaseCurrency(), r.GetQuoteCurrency(), r.GetRate(), r.GetSpreadBps())
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, status.Errorf(codes.Internal, "failed to store fx rate: %v", err)
+		}
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// CreateFxQuote locks the current rate for converting srcCents for fxQuoteTTL.
+// The quoted destination amount already has the spread deducted.
+func (impl *Implementation) CreateFxQuote(ctx context.Context, fxQuotePayload *pb.FxQuotePayload) (*pb.FxQuote, error) {
+	if err := validateCurrencyPair(fxQuotePayload.GetSrcCurrency(), fxQuotePayload.GetDstCurrency()); err != nil {
+		return nil, err
+	}
+	if fxQuotePayload.GetSrcCents() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "quote amount must be positive")
+	}
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	midRate, spreadBps, err := fetchFxRate(tx, fxQuotePayload.SrcCurrency, fxQuotePayload.DstCurrency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// The customer rate is the mid rate minus the spread, which the house keeps.
+	// Both are rounded to the precision they are stored with.
+	rate := new(big.Rat).Mul(midRate, big.NewRat(int64(10000-spreadBps), 10000)).FloatString(12)
+	customerRate, _ := new(big.Rat).SetString(rate)
+	midRate.SetString(midRate.FloatString(12))
+	dstCents := convertAmount(fxQuotePayload.SrcCents, customerRate, fxQuotePayload.SrcCurrency, fxQuotePayload.DstCurrency)
+	spreadCents := convertAmount(fxQuotePayload.SrcCents, midRate, fxQuotePayload.SrcCurrency, fxQuotePayload.DstCurrency) - dstCents
+	if dstCents <= 0 {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "quote amount is too small to convert")
+	}
+
+	quoteId := uuid.NewString()
+	stmt, err := tx.Prepare(insertFxQuoteQuery)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	_, err = stmt.Exec(quoteId, fxQuotePayload.SrcCurrency, fxQuotePayload.DstCurrency, fxQuotePayload.SrcCents, dstCents, spreadCents, rate, midRate.FloatString(12), int64(fxQuoteTTL.Seconds()))
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to insert fx quote: %v", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &pb.FxQuote{
+		QuoteId:     quoteId,
+		SrcCurrency: fxQuotePayload.SrcCurrency,
+		DstCurrency: fxQuotePayload.DstCurrency,
+		SrcCents:    fxQuotePayload.SrcCents,
+		DstCents:    dstCents,
+		Rate:        rate,
+		ExpiresAt:   time.Now().Add(fxQuoteTTL).Unix(),
+	}, nil
+}
+
+func validateCurrencyPair(src, dst string) error {
+	if err := currency.Validate(src); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := currency.Validate(dst); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if src == dst {
+		return status.Errorf(codes.InvalidArgument, "cannot convert %s to itself", src)
+	}
+	return nil
+}
+
+// fetchFxRate returns the mid rate from src to dst, inverting the dst/src rate
+// when only that direction has been loaded.
+func fetchFxRate(tx *sql.Tx, src, dst string) (*big.Rat, int32, error) {
+	stmt, err := tx.Prepare(selectFxRateQuery)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	var rate string
+	var spreadBps int32
+	inverse := false
+	err = stmt.QueryRow(src, dst).Scan(&rate, &spreadBps)
+	if errors.Is(err, sql.ErrNoRows) {
+		inverse = true
+		err = stmt.QueryRow(dst, src).Scan(&rate, &spreadBps)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, status.Errorf(codes.NotFound, "no fx rate for %s/%s", src, dst)
+		}
+		return nil, 0, status.Errorf(codes.Internal, "failed to query fx rate: %v", err)
+	}
+
+	midRate, ok := new(big.Rat).SetString(rate)
+	if !ok || midRate.Sign() <= 0 {
+		return nil, 0, status.Errorf(codes.Internal, "invalid stored fx rate %q for %s/%s", rate, src, dst)
+	}
+	if inverse {
+		midRate.Inv(midRate)
+	}
+
+	return midRate, spreadBps, nil
+}
+
+func fetchFxQuote(tx *sql.Tx, quoteId string) (fxQuote, error) {
+	var q fxQuote
+	stmt, err := tx.Prepare(selectFxQuoteQuery)
+	if err != nil {
+		return q, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = stmt.QueryRow(quoteId).Scan(&q.quoteId, &q.srcCurrency, &q.dstCurrency, &q.srcCents, &q.dstCents, &q.rate, &q.midRate, &q.pid, &q.active)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return q, status.Errorf(codes.NotFound, "fx quote not found: %s", quoteId)
+		}
+		return q, dbError("failed to query fx quote", err)
+	}
+	return q, nil
+}
+
+// useFxQuote checks that quote can pay for an authorization of amount in
+// currencyCode and binds it to pid so it cannot be used twice.
+func useFxQuote(tx *sql.Tx, quote fxQuote, pid, currencyCode string, amount int64) error {
+	err := checkFxQuote(quote, currencyCode, amount)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(useFxQuoteQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	_, err = stmt.Exec(pid, quote.quoteId)
+	if err != nil {
+		return dbError("failed to update fx quote", err)
+	}
+	return nil
+}
+
+func checkFxQuote(quote fxQuote, currencyCode string, amount int64) error {
+	if quote.pid != "" {
+		return status.Errorf(codes.FailedPrecondition, "fx quote %s has already been used", quote.quoteId)
+	}
+	if !quote.active {
+		return status.Errorf(codes.FailedPrecondition, "fx quote %s has expired", quote.quoteId)
+	}
+	if quote.srcCurrency != currencyCode || quote.srcCents != amount {
+		return status.Errorf(codes.InvalidArgument, "fx quote %s is for %d %s", quote.quoteId, quote.srcCents, quote.srcCurrency)
+	}
+	return nil
+}
+
+// convertAmount converts amount in minor units of src into minor units of dst
+// at rate, rounding down.
+func convertAmount(amount int64, rate *big.Rat, src, dst string) int64 {
+	srcExponent, _ := currency.Exponent(src)
+	dstExponent, _ := currency.Exponent(dst)
+
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(dstExponent-srcExponent))), nil))
+	if dstExponent >= srcExponent {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	return new(big.Int).Quo(value.Num(), value.Denom()).Int64()
+}
+
+// convertAtRate converts amount with a decimal rate string stored on a transaction.
+func convertAtRate(amount int64, rate string, src, dst string) (int64, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return 0, status.Errorf(codes.Internal, "invalid fx rate %q", rate)
+	}
+	return convertAmount(amount, r, src, dst), nil
+}
+
+// convertBack converts an amount in dst back into src at the inverse of rate.
+func convertBack(amount int64, rate string, src, dst string) (int64, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return 0, status.Errorf(codes.Internal, "invalid fx rate %q", rate)
+	}
+	return convertAmount(amount, r.Inv(r), dst, src), nil
+}
+
+// fxTransfer debits srcAmount from srcAccount into the house FX account of its
+// currency and pays dstAmount out of the house FX account of dstAccount's
+// currency. A positive spread is booked from house FX to house FX_REVENUE.
+func fxTransfer(tx unitOfWork, srcAccount, dstAccount account, srcAmount, dstAmount, spread int64) error {
+	houseWallet, err := tx.fetchWallet(houseWalletUserId)
+	if err != nil {
+		return err
+	}
+
+	houseSrcAccount, err := tx.fetchAccount(houseWallet.ID, "FX", srcAccount.currency)
+	if err != nil {
+		return err
+	}
+
+	houseDstAccount, err := tx.fetchAccount(houseWallet.ID, "FX", dstAccount.currency)
+	if err != nil {
+		return err
+	}
+
+	err = tx.transfer(srcAccount, houseSrcAccount, srcAmount)
+	if err != nil {
+		return err
+	}
+
+	err = tx.transfer(houseDstAccount, dstAccount, dstAmount)
+	if err != nil {
+		return err
+	}
+
+	if spread <= 0 {
+		return nil
+	}
+
+	revenueAccount, err := tx.fetchAccount(houseWallet.ID, "FX_REVENUE", dstAccount.currency)
+	if err != nil {
+		return err
+	}
+
+	return tx.transfer(houseDstAccount, revenueAccount, spread)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,141 @@
+package mm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// listTransactionsQuery is completed with the filters of the request. Rows are
+// ordered newest first by (created_at, id) so that pages are stable even when
+// several transactions share a timestamp.
+const listTransactionsQuery = `SELECT t.id, t.pid, t.transaction_type, COALESCE(t.capture_id, ''), COALESCE(p.status, ''),
+	t.src_user_id, t.dst_user_id, t.src_account_wallet_id, t.dst_account_wallet_id, t.src_account_type, t.dst_account_type,
+	t.amount, t.currency, t.converted_amount, t.converted_currency, t.memo, UNIX_TIMESTAMP(t.created_at)
+	FROM transactions t LEFT JOIN payments p ON p.pid = t.pid`
+
+// pageCursor identifies the last transaction of a page.
+type pageCursor struct {
+	createdAt int64
+	id        int32
+}
+
+func (c pageCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", c.createdAt, c.id))
+}
+
+func decodePageCursor(token string) (pageCursor, error) {
+	var c pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, status.Errorf(codes.InvalidArgument, "invalid page token")
+	}
+	_, err = fmt.Sscanf(string(raw), "%d:%d", &c.createdAt, &c.id)
+	if err != nil {
+		return c, status.Errorf(codes.InvalidArgument, "invalid page token")
+	}
+	return c, nil
+}
+
+func (impl *Implementation) ListTransactions(ctx context.Context, listTransactionsPayload *pb.ListTransactionsPayload) (*pb.ListTransactionsResponse, error) {
+	// Everyone but admins is limited to their own transactions
+	c := callerFromContext(ctx)
+	if !c.isAdmin() && !c.owns(listTransactionsPayload.GetUserId()) {
+		return nil, status.Errorf(codes.PermissionDenied, "only admins may list transactions of other users")
+	}
+
+	pageSize := int(listTransactionsPayload.GetPageSize())
+	if pageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page size must not be negative")
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var conditions []string
+	var args []any
+
+	if userId := listTransactionsPayload.GetUserId(); userId != "" {
+		conditions = append(conditions, "(t.src_user_id = ? OR t.dst_user_id = ?)")
+		args = append(args, userId, userId)
+	}
+	if walletId := listTransactionsPayload.GetWalletId(); walletId != 0 {
+		conditions = append(conditions, "(t.src_account_wallet_id = ? OR t.dst_account_wallet_id = ?)")
+		args = append(args, walletId, walletId)
+	}
+	if accountType := listTransactionsPayload.GetAccountType(); accountType != "" {
+		conditions = append(conditions, "(t.src_account_type = ? OR t.dst_account_type = ?)")
+		args = append(args, accountType, accountType)
+	}
+	if paymentStatus := listTransactionsPayload.GetStatus(); paymentStatus != "" {
+		if !isPaymentStatus(paymentStatus) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown payment status: %s", paymentStatus)
+		}
+		conditions = append(conditions, "p.status = ?")
+		args = append(args, paymentStatus)
+	}
+	if createdFrom := listTransactionsPayload.GetCreatedFrom(); createdFrom != 0 {
+		conditions = append(conditions, "t.created_at >= FROM_UNIXTIME(?)")
+		args = append(args, createdFrom)
+	}
+	if createdTo := listTransactionsPayload.GetCreatedTo(); createdTo != 0 {
+		conditions = append(conditions, "t.created_at < FROM_UNIXTIME(?)")
+		args = append(args, createdTo)
+	}
+	if pageToken := listTransactionsPayload.GetPageToken(); pageToken != "" {
+		cursor, err := decodePageCursor(pageToken)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "(t.created_at, t.id) < (FROM_UNIXTIME(?), ?)")
+		args = append(args, cursor.createdAt, cursor.id)
+	}
+
+	query := listTransactionsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	// Fetch one extra row to know whether there is another page
+	query += " ORDER BY t.created_at DESC, t.id DESC LIMIT ?"
+	args = append(args, pageSize+1)
+
+	rows, err := impl.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query transactions: %v", err)
+	}
+	defer rows.Close()
+
+	response := &pb.ListTransactionsResponse{}
+	for rows.Next() {
+		var t pb.Transaction
+		err := rows.Scan(&t.Id, &t.Pid, &t.TransactionType, &t.CaptureId, &t.Status, &t.SrcUserId, &t.DstUserId, &t.SrcWalletId, &t.DstWalletId, &t.SrcAccountType, &t.DstAccountType, &t.Amount, &t.Currency, &t.ConvertedAmount, &t.ConvertedCurrency, &t.Memo, &t.CreatedAt)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan transaction: %v", err)
+		}
+		response.Transactions = append(response.Transactions, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query transactions: %v", err)
+	}
+
+	if len(response.Transactions) > pageSize {
+		response.Transactions = response.Transactions[:pageSize]
+		last := response.Transactions[pageSize-1]
+		response.NextPageToken = pageCursor{createdAt: last.CreatedAt, id: last.Id}.encode()
+	}
+
+	return response, nil
+}
@@ -0,0 +1,113 @@
+package mm
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	idempotencyKeyHeader = "idempotency-key"
+
+	selectIdempotencyKeyQuery = "SELECT fingerprint, response FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND method = ? FOR UPDATE"
+	insertIdempotencyKeyQuery = "INSERT INTO idempotency_keys (user_id, idempotency_key, method, fingerprint, response) VALUES (?, ?, ?, ?, ?)"
+)
+
+// idempotencyKeyFromContext returns the Idempotency-Key forwarded by the gateway
+// as gRPC metadata, or an empty string when the caller did not send one.
+func idempotencyKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(idempotencyKeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func requestFingerprint(req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lookupIdempotentResponse locks the idempotency key and, if it was used before,
+// unmarshals the stored response into resp. Keys are scoped to the calling
+// user, so two callers that pick the same key never see each other's requests.
+// A key that was used with a different request body is rejected.
+func lookupIdempotentResponse(tx *sql.Tx, userId, key, method string, req, resp proto.Message) (string, bool, error) {
+	if key == "" {
+		return "", false, nil
+	}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return "", false, err
+	}
+
+	var storedFingerprint string
+	var storedResponse []byte
+	stmt, err := tx.Prepare(selectIdempotencyKeyQuery)
+	if err != nil {
+		return "", false, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = stmt.QueryRow(userId, key, method).Scan(&storedFingerprint, &storedResponse)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fingerprint, false, nil
+		}
+		return "", false, dbError("failed to query idempotency key", err)
+	}
+
+	if storedFingerprint != fingerprint {
+		return "", false, status.Errorf(codes.InvalidArgument, "idempotency key %s was already used with a different request", key)
+	}
+
+	err = proto.Unmarshal(storedResponse, resp)
+	if err != nil {
+		return "", false, status.Errorf(codes.Internal, "failed to decode stored response: %v", err)
+	}
+
+	return fingerprint, true, nil
+}
+
+// saveIdempotentResponse stores the response for the caller's key so replays
+// can return it.
+func saveIdempotentResponse(tx *sql.Tx, userId, key, method, fingerprint string, resp proto.Message) error {
+	if key == "" {
+		return nil
+	}
+
+	b, err := proto.Marshal(resp)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+
+	stmt, err := tx.Prepare(insertIdempotencyKeyQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	_, err = stmt.Exec(userId, key, method, fingerprint, b)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return status.Errorf(codes.Aborted, "a concurrent request with idempotency key %s is in progress", key)
+		}
+		return dbError("failed to store idempotency key", err)
+	}
+
+	return nil
+}
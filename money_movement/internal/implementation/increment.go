@@ -0,0 +1,238 @@
+package mm
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IncrementAuthorization raises the amount held by an open authorization, e.g.
+// when a hotel stay runs longer or a tip is added before capture. The extra
+// funds move from the customer's DEFAULT to PAYMENT account under the same pid
+// and every increment is recorded as its own INCREMENT transaction.
+func (impl *Implementation) IncrementAuthorization(ctx context.Context, incrementPayload *pb.IncrementAuthorizationPayload) (*pb.IncrementAuthorizationResponse, error) {
+	return retryOnConflict(ctx, func() (*pb.IncrementAuthorizationResponse, error) {
+		return impl.incrementAuthorization(ctx, incrementPayload)
+	})
+}
+
+func (impl *Implementation) incrementAuthorization(ctx context.Context, incrementPayload *pb.IncrementAuthorizationPayload) (*pb.IncrementAuthorizationResponse, error) {
+	amount := incrementPayload.GetAdditionalCents()
+	if amount <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "additional amount must be positive")
+	}
+
+	//Begin a transaction
+	tx, err := impl.store.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := tx.fetchPayment(incrementPayload.GetPid())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Only the customer can spend more from their wallet
+	err = callerFromContext(ctx).requireOwner(p.customerUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Replay the stored response if this request was already processed
+	idempotencyKey := idempotencyKeyFromContext(ctx)
+	var storedResponse pb.IncrementAuthorizationResponse
+	fingerprint, replay, err := tx.lookupIdempotentResponse(callerFromContext(ctx).userId, idempotencyKey, "IncrementAuthorization", incrementPayload, &storedResponse)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	if replay {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return &storedResponse, nil
+	}
+
+	err = impl.checkIncrementable(p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	authorizeTransaction, err := tx.fetchTransaction(p.pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// A quote locks the rate for the amount it was created for only
+	if authorizeTransaction.fxQuoteId != "" {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "payment %s was authorized with an fx quote and cannot be raised", p.pid)
+	}
+
+	custWallet, err := tx.fetchWallet(p.customerUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	merchantWallet, err := tx.fetchWalletWithWalletId(p.merchantWalletId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	if merchantWallet.status != walletStatusActive || custWallet.status != walletStatusActive {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "wallet is closed")
+	}
+
+	// Increments count towards the same spending controls as authorizations
+	limit, err := tx.fetchVelocityLimit(custWallet, p.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	usage, err := tx.fetchAuthorizationUsage(custWallet.UserId, p.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = checkIncrementVelocity(limit, usage, p.authorizedCents, amount, p.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	srcAccount, err := tx.fetchAccount(custWallet.ID, "DEFAULT", p.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	dstAccount, err := tx.fetchAccount(custWallet.ID, "PAYMENT", p.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.transfer(srcAccount, dstAccount, amount)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	incrementTransaction := newTransaction(p.pid, transactionTypeIncrement, srcAccount, dstAccount, custWallet, custWallet, merchantWallet, amount)
+	err = tx.insertTransaction(incrementTransaction)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	p.authorizedCents += amount
+	err = tx.updatePayment(p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	response := &pb.IncrementAuthorizationResponse{
+		Pid:             p.pid,
+		AuthorizedCents: p.authorizedCents,
+		RemainingCents:  p.remaining(),
+	}
+
+	err = tx.saveIdempotentResponse(callerFromContext(ctx).userId, idempotencyKey, "IncrementAuthorization", fingerprint, response)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// End the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return response, nil
+}
+
+// checkIncrementable rejects raising payments that no longer hold funds, that
+// the expiry sweeper is due to release, and split payments, whose legs fix
+// how much each merchant gets.
+func (impl *Implementation) checkIncrementable(p payment) error {
+	if p.status != paymentStatusAuthorized && p.status != paymentStatusPartiallyCaptured {
+		return status.Errorf(codes.FailedPrecondition, "payment %s is %s and cannot be raised", p.pid, p.status)
+	}
+	if impl.authorizationTTL > 0 && !time.Unix(p.createdAt, 0).After(time.Now().Add(-impl.authorizationTTL)) {
+		return status.Errorf(codes.FailedPrecondition, "authorization %s has expired", p.pid)
+	}
+	if len(p.legs) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "split payment %s cannot be raised", p.pid)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Postings are written from the account holder's point of view: a DEBIT
+// takes money out of an account and a CREDIT puts money into it, so an
+// account's balance is the sum of its credits minus the sum of its debits.
+const (
+	postingDebit  = "DEBIT"
+	postingCredit = "CREDIT"
+)
+
+const (
+	insertJournalEntryQuery   = "INSERT INTO journal_entries (currency) VALUES (?)"
+	insertJournalPostingQuery = "INSERT INTO journal_postings (entry_id, account_id, direction, cents) VALUES (?, ?, ?, ?), (?, ?, ?, ?)"
+
+	// selectJournalBalancesQuery rebuilds every account's balance from its
+	// postings next to the balance stored on the account.
+	selectJournalBalancesQuery = `SELECT a.id, a.wallet_id, a.account_type, a.currency, a.cents,
+	COALESCE(SUM(CASE WHEN p.direction = 'CREDIT' THEN p.cents ELSE -p.cents END), 0)
+	FROM accounts a LEFT JOIN journal_postings p ON p.account_id = a.id
+	GROUP BY a.id, a.wallet_id, a.account_type, a.currency, a.cents
+	ORDER BY a.id`
+	selectUnbalancedEntriesQuery = `SELECT entry_id FROM journal_postings
+	GROUP BY entry_id HAVING SUM(CASE WHEN direction = 'CREDIT' THEN cents ELSE -cents END) <> 0
+	ORDER BY entry_id`
+)
+
+// postJournalEntry records a movement of amount from srcAccount to dstAccount
+// as one entry with a balanced debit and credit posting.
+func postJournalEntry(tx *sql.Tx, srcAccount, dstAccount account, amount int64) error {
+	stmt, err := tx.Prepare(insertJournalEntryQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert journal entry statement: %v", err)
+	}
+
+	res, err := stmt.Exec(srcAccount.currency)
+	if err != nil {
+		return dbError("failed to insert journal entry", err)
+	}
+
+	entryId, err := res.LastInsertId()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get journal entry id: %v", err)
+	}
+
+	stmt, err = tx.Prepare(insertJournalPostingQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert journal posting statement: %v", err)
+	}
+
+	_, err = stmt.Exec(entryId, srcAccount.ID, postingDebit, amount, entryId, dstAccount.ID, postingCredit, amount)
+	if err != nil {
+		return dbError("failed to insert journal postings", err)
+	}
+
+	return nil
+}
+
+// VerifyJournal recomputes every account balance from the journal and
+// reports the accounts whose stored balance has drifted from it, along with
+// any entry whose postings do not balance.
+func (impl *Implementation) VerifyJournal(ctx context.Context, _ *emptypb.Empty) (*pb.JournalReport, error) {
+	if err := callerFromContext(ctx).requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	//Begin a transaction so balances and postings are read from one snapshot
+	tx, err := impl.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	report, err := verifyBalances(tx)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	report.UnbalancedEntryIds, err = fetchUnbalancedEntries(tx)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return report, nil
+}
+
+func verifyBalances(tx *sql.Tx) (*pb.JournalReport, error) {
+	stmt, err := tx.Prepare(selectJournalBalancesQuery)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	rows, err := stmt.Query()
+	if err != nil {
+		return nil, dbError("failed to query journal balances", err)
+	}
+	defer rows.Close()
+
+	report := &pb.JournalReport{}
+	for rows.Next() {
+		var d pb.AccountDrift
+		err = rows.Scan(&d.AccountId, &d.WalletId, &d.AccountType, &d.Currency, &d.Cents, &d.JournalCents)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan journal balance: %v", err)
+		}
+		report.AccountsChecked++
+		if d.Cents != d.JournalCents {
+			report.Drifts = append(report.Drifts, &d)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, dbError("failed to read journal balances", err)
+	}
+
+	return report, nil
+}
+
+func fetchUnbalancedEntries(tx *sql.Tx) ([]int64, error) {
+	stmt, err := tx.Prepare(selectUnbalancedEntriesQuery)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	rows, err := stmt.Query()
+	if err != nil {
+		return nil, dbError("failed to query journal entries", err)
+	}
+	defer rows.Close()
+
+	var entryIds []int64
+	for rows.Next() {
+		var entryId int64
+		err = rows.Scan(&entryId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan journal entry: %v", err)
+		}
+		entryIds = append(entryIds, entryId)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, dbError("failed to read journal entries", err)
+	}
+
+	return entryIds, nil
+}
@@ -0,0 +1,437 @@
+package mm
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/producer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// memoryStore keeps everything in memory. A unit of work holds the store's
+// lock from begin until it ends and works on a copy of the state, which
+// replaces the store's state on Commit. Units of work are therefore
+// serialized, and a rolled back one leaves no trace.
+type memoryStore struct {
+	mu    sync.Mutex
+	state *memoryState
+}
+
+type memoryState struct {
+	lastId int32
+
+	wallets      map[int32]wallet
+	accounts     map[int32]account
+	transactions []memoryTransaction
+	payments     map[string]payment
+	fxQuotes     map[string]memoryFxQuote
+	feeSchedules map[memoryFeeScheduleKey]feeSchedule
+	limits       map[memoryVelocityLimitKey]velocityLimit
+	decisions    []riskDecision
+	idempotency  map[memoryIdempotencyKey]memoryIdempotentResponse
+	postings     []memoryPosting
+	outbox       []memoryOutboxMessage
+}
+
+type memoryTransaction struct {
+	transaction
+	createdAt time.Time
+}
+
+type memoryFxQuote struct {
+	quote     fxQuote
+	expiresAt time.Time
+}
+
+type memoryFeeScheduleKey struct {
+	merchantWalletId int32
+	currency         string
+}
+
+// memoryVelocityLimitKey has either walletType or walletId set, like the
+// velocity_limits table.
+type memoryVelocityLimitKey struct {
+	walletType string
+	walletId   int32
+	currency   string
+}
+
+type memoryIdempotencyKey struct {
+	userId string
+	key    string
+	method string
+}
+
+type memoryIdempotentResponse struct {
+	fingerprint string
+	response    []byte
+}
+
+type memoryPosting struct {
+	entryId   int32
+	accountId int32
+	direction string
+	cents     int64
+}
+
+// memoryOutboxMessage is a capture or refund event waiting to be published.
+type memoryOutboxMessage struct {
+	kind     string
+	pid      string
+	userId   string
+	amount   int64
+	currency string
+	legs     []producer.CaptureLeg
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		state: &memoryState{
+			wallets:      make(map[int32]wallet),
+			accounts:     make(map[int32]account),
+			payments:     make(map[string]payment),
+			fxQuotes:     make(map[string]memoryFxQuote),
+			feeSchedules: make(map[memoryFeeScheduleKey]feeSchedule),
+			limits:       make(map[memoryVelocityLimitKey]velocityLimit),
+			idempotency:  make(map[memoryIdempotencyKey]memoryIdempotentResponse),
+		},
+	}
+}
+
+// addWallet, addAccount, addFxQuote, setFeeSchedule and setVelocityLimit seed
+// the store outside of any unit of work.
+func (s *memoryStore) addWallet(userId, walletType string) wallet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.lastId++
+	w := wallet{ID: s.state.lastId, UserId: userId, walletType: walletType, status: walletStatusActive, createdAt: time.Now().Unix()}
+	s.state.wallets[w.ID] = w
+	return w
+}
+
+func (s *memoryStore) addAccount(walletId int32, accountType, currencyCode string, cents int64) account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.lastId++
+	a := account{ID: s.state.lastId, cents: cents, accountType: accountType, currency: currencyCode, walletID: walletId}
+	s.state.accounts[a.ID] = a
+	return a
+}
+
+func (s *memoryStore) addFxQuote(quote fxQuote, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.fxQuotes[quote.quoteId] = memoryFxQuote{quote: quote, expiresAt: expiresAt}
+}
+
+func (s *memoryStore) setFeeSchedule(merchantWalletId int32, currencyCode string, schedule feeSchedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.feeSchedules[memoryFeeScheduleKey{merchantWalletId, currencyCode}] = schedule
+}
+
+func (s *memoryStore) setVelocityLimit(walletType string, walletId int32, currencyCode string, limit velocityLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.limits[memoryVelocityLimitKey{walletType, walletId, currencyCode}] = limit
+}
+
+func (s *memoryStore) begin(ctx context.Context) (unitOfWork, error) {
+	s.mu.Lock()
+	return &memoryUnitOfWork{store: s, state: s.state.clone()}, nil
+}
+
+func (st *memoryState) clone() *memoryState {
+	return &memoryState{
+		lastId:       st.lastId,
+		wallets:      maps.Clone(st.wallets),
+		accounts:     maps.Clone(st.accounts),
+		transactions: slices.Clone(st.transactions),
+		payments:     maps.Clone(st.payments),
+		fxQuotes:     maps.Clone(st.fxQuotes),
+		feeSchedules: maps.Clone(st.feeSchedules),
+		limits:       maps.Clone(st.limits),
+		decisions:    slices.Clone(st.decisions),
+		idempotency:  maps.Clone(st.idempotency),
+		postings:     slices.Clone(st.postings),
+		outbox:       slices.Clone(st.outbox),
+	}
+}
+
+type memoryUnitOfWork struct {
+	store *memoryStore
+	state *memoryState
+	done  bool
+}
+
+func (u *memoryUnitOfWork) Commit() error {
+	if u.done {
+		return status.Errorf(codes.FailedPrecondition, "unit of work has already ended")
+	}
+	u.done = true
+	u.store.state = u.state
+	u.store.mu.Unlock()
+	return nil
+}
+
+func (u *memoryUnitOfWork) Rollback() error {
+	if u.done {
+		return status.Errorf(codes.FailedPrecondition, "unit of work has already ended")
+	}
+	u.done = true
+	u.store.mu.Unlock()
+	return nil
+}
+
+func (u *memoryUnitOfWork) lookupIdempotentResponse(userId, key, method string, req, resp proto.Message) (string, bool, error) {
+	if key == "" {
+		return "", false, nil
+	}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return "", false, err
+	}
+
+	stored, ok := u.state.idempotency[memoryIdempotencyKey{userId, key, method}]
+	if !ok {
+		return fingerprint, false, nil
+	}
+
+	if stored.fingerprint != fingerprint {
+		return "", false, status.Errorf(codes.InvalidArgument, "idempotency key %s was already used with a different request", key)
+	}
+
+	err = proto.Unmarshal(stored.response, resp)
+	if err != nil {
+		return "", false, status.Errorf(codes.Internal, "failed to decode stored response: %v", err)
+	}
+
+	return fingerprint, true, nil
+}
+
+func (u *memoryUnitOfWork) saveIdempotentResponse(userId, key, method, fingerprint string, resp proto.Message) error {
+	if key == "" {
+		return nil
+	}
+
+	b, err := proto.Marshal(resp)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+
+	k := memoryIdempotencyKey{userId, key, method}
+	if _, ok := u.state.idempotency[k]; ok {
+		return status.Errorf(codes.Aborted, "a concurrent request with idempotency key %s is in progress", key)
+	}
+	u.state.idempotency[k] = memoryIdempotentResponse{fingerprint: fingerprint, response: b}
+
+	return nil
+}
+
+func (u *memoryUnitOfWork) fetchWallet(userId string) (wallet, error) {
+	for _, w := range u.state.wallets {
+		if w.UserId == userId {
+			return w, nil
+		}
+	}
+	return wallet{}, status.Errorf(codes.NotFound, "wallet not found for user: %s", userId)
+}
+
+func (u *memoryUnitOfWork) fetchWalletWithWalletId(walletId int32) (wallet, error) {
+	w, ok := u.state.wallets[walletId]
+	if !ok {
+		return w, status.Errorf(codes.NotFound, "wallet not found for ID: %d", walletId)
+	}
+	return w, nil
+}
+
+func (u *memoryUnitOfWork) fetchAccount(walletId int32, accountType, currencyCode string) (account, error) {
+	for _, a := range u.state.accounts {
+		if a.walletID == walletId && a.accountType == accountType && a.currency == currencyCode {
+			return a, nil
+		}
+	}
+	return account{}, status.Errorf(codes.NotFound, "account not found for wallet ID: %d, type: %s and currency: %s", walletId, accountType, currencyCode)
+}
+
+func (u *memoryUnitOfWork) transfer(srcAccount, dstAccount account, amount int64) error {
+	err := validateTransfer(srcAccount, dstAccount, amount)
+	if err != nil {
+		return err
+	}
+
+	src, ok := u.state.accounts[srcAccount.ID]
+	if !ok {
+		return status.Errorf(codes.Internal, "failed to update source account: account %d not found", srcAccount.ID)
+	}
+	dst, ok := u.state.accounts[dstAccount.ID]
+	if !ok {
+		return status.Errorf(codes.Internal, "failed to update destination account: account %d not found", dstAccount.ID)
+	}
+
+	if src.cents < amount {
+		return status.Errorf(codes.FailedPrecondition, "insufficient funds in source account")
+	}
+	src.cents -= amount
+	u.state.accounts[src.ID] = src
+	// Read dst again in case it is the same account as src
+	dst = u.state.accounts[dst.ID]
+	dst.cents += amount
+	u.state.accounts[dst.ID] = dst
+
+	u.state.lastId++
+	u.state.postings = append(u.state.postings,
+		memoryPosting{entryId: u.state.lastId, accountId: src.ID, direction: postingDebit, cents: amount},
+		memoryPosting{entryId: u.state.lastId, accountId: dst.ID, direction: postingCredit, cents: amount},
+	)
+
+	return nil
+}
+
+func (u *memoryUnitOfWork) insertTransaction(t transaction) error {
+	u.state.lastId++
+	t.ID = u.state.lastId
+	u.state.transactions = append(u.state.transactions, memoryTransaction{transaction: t, createdAt: time.Now()})
+	return nil
+}
+
+func (u *memoryUnitOfWork) fetchTransaction(pid string) (transaction, error) {
+	for _, t := range u.state.transactions {
+		if t.pid == pid && t.transactionType == transactionTypeAuthorize {
+			return t.transaction, nil
+		}
+	}
+	return transaction{}, status.Errorf(codes.NotFound, "transaction not found for pid: %s", pid)
+}
+
+func (u *memoryUnitOfWork) insertPayment(p payment) error {
+	if _, ok := u.state.payments[p.pid]; ok {
+		return status.Errorf(codes.Internal, "failed to insert payment: duplicate pid %s", p.pid)
+	}
+	now := time.Now().Unix()
+	p.createdAt, p.updatedAt = now, now
+	u.state.payments[p.pid] = p
+	return nil
+}
+
+func (u *memoryUnitOfWork) fetchPayment(pid string) (payment, error) {
+	p, ok := u.state.payments[pid]
+	if !ok {
+		return p, status.Errorf(codes.NotFound, "payment not found for pid: %s", pid)
+	}
+	// The legs are shared with the committed state until this unit commits
+	p.legs = slices.Clone(p.legs)
+	return p, nil
+}
+
+func (u *memoryUnitOfWork) updatePayment(p payment) error {
+	if _, ok := u.state.payments[p.pid]; !ok {
+		return status.Errorf(codes.Internal, "failed to update payment: payment %s not found", p.pid)
+	}
+	p.updatedAt = time.Now().Unix()
+	u.state.payments[p.pid] = p
+	return nil
+}
+
+func (u *memoryUnitOfWork) fetchFxQuote(quoteId string) (fxQuote, error) {
+	q, ok := u.state.fxQuotes[quoteId]
+	if !ok {
+		return fxQuote{}, status.Errorf(codes.NotFound, "fx quote not found: %s", quoteId)
+	}
+	q.quote.active = time.Now().Before(q.expiresAt)
+	return q.quote, nil
+}
+
+func (u *memoryUnitOfWork) useFxQuote(quote fxQuote, pid, currencyCode string, amount int64) error {
+	err := checkFxQuote(quote, currencyCode, amount)
+	if err != nil {
+		return err
+	}
+
+	q := u.state.fxQuotes[quote.quoteId]
+	q.quote.pid = pid
+	u.state.fxQuotes[quote.quoteId] = q
+	return nil
+}
+
+func (u *memoryUnitOfWork) fetchFeeSchedule(merchantWalletId int32, currencyCode string) (feeSchedule, error) {
+	return u.state.feeSchedules[memoryFeeScheduleKey{merchantWalletId, currencyCode}], nil
+}
+
+func (u *memoryUnitOfWork) fetchCapturedFees(pid string, merchantWalletId int32) (int64, int64, int64, error) {
+	var gross, fee, returned int64
+	for _, t := range u.state.transactions {
+		if t.pid != pid || t.finalDstMerchantWalletId != merchantWalletId {
+			continue
+		}
+		switch t.transactionType {
+		case transactionTypeCapture:
+			gross += t.convertedAmount
+			fee += t.feeAmount
+		case transactionTypeRefund:
+			returned += t.feeAmount
+		}
+	}
+	return gross, fee, returned, nil
+}
+
+func (u *memoryUnitOfWork) fetchVelocityLimit(w wallet, currencyCode string) (velocityLimit, error) {
+	if l, ok := u.state.limits[memoryVelocityLimitKey{walletId: w.ID, currency: currencyCode}]; ok {
+		return l, nil
+	}
+	return u.state.limits[memoryVelocityLimitKey{walletType: w.walletType, currency: currencyCode}], nil
+}
+
+func (u *memoryUnitOfWork) fetchAuthorizationUsage(userId, currencyCode string) (authorizationUsage, error) {
+	var usage authorizationUsage
+	now := time.Now()
+	year, month, day := now.Date()
+	startOfDay := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	for _, t := range u.state.transactions {
+		if t.srcUserId != userId || t.currency != currencyCode {
+			continue
+		}
+		// Released amounts are taken off as of when the payment was authorized
+		amount, at := t.amount, t.createdAt
+		switch t.transactionType {
+		case transactionTypeAuthorize, transactionTypeIncrement:
+		case transactionTypeVoid, transactionTypeExpire, transactionTypeRelease:
+			amount, at = -t.amount, time.Unix(u.state.payments[t.pid].createdAt, 0)
+		default:
+			continue
+		}
+		if !at.Before(startOfDay) {
+			usage.dailyCents += amount
+		}
+		if !at.Before(startOfMonth) {
+			usage.monthlyCents += amount
+		}
+		if t.transactionType == transactionTypeAuthorize && t.createdAt.After(now.Add(-time.Hour)) {
+			usage.hourlyCount++
+		}
+	}
+	return usage, nil
+}
+
+func (u *memoryUnitOfWork) insertRiskDecision(d riskDecision) error {
+	u.state.decisions = append(u.state.decisions, d)
+	return nil
+}
+
+func (u *memoryUnitOfWork) enqueueCaptureMessage(pid, userId string, amount int64, currencyCode string, legs []producer.CaptureLeg) error {
+	u.state.outbox = append(u.state.outbox, memoryOutboxMessage{kind: transactionTypeCapture, pid: pid, userId: userId, amount: amount, currency: currencyCode, legs: legs})
+	return nil
+}
+
+func (u *memoryUnitOfWork) enqueueRefundMessage(pid, userId string, amount int64, currencyCode, merchantUserId string, fee producer.CaptureFee) error {
+	legs := []producer.CaptureLeg{{MerchantUserId: merchantUserId, Amount: amount, Fee: fee}}
+	u.state.outbox = append(u.state.outbox, memoryOutboxMessage{kind: transactionTypeRefund, pid: pid, userId: userId, amount: amount, currency: currencyCode, legs: legs})
+	return nil
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log"
+	"time"
 
+	"github.com/MikePham0630/gomicro/internal/bank"
+	"github.com/MikePham0630/gomicro/internal/currency"
 	"github.com/MikePham0630/gomicro/internal/producer"
+	"github.com/MikePham0630/gomicro/internal/risk"
 	pb "github.com/MikePham0630/gomicro/proto"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -14,33 +19,135 @@ import (
 )
 
 const (
-	insertTransactionQuery = "INSERT INTO transactions (pid, src_user_id, dst_user_id, src_account_wallet_id, dst_account_wallet_id, src_account_id, dst_account_id, src_account_type, dst_account_type, final_dst_merchant_wallet_id, amount) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	selecTractionQuery     = "SELECT id, pid, src_user_id, dst_user_id, src_account_wallet_id, dst_account_wallet_id, src_account_id, dst_account_id, src_account_type, dst_account_type, final_dst_merchant_wallet_id, amount FROM transactions WHERE pid = ?"
+	insertTransactionQuery = "INSERT INTO transactions (pid, transaction_type, capture_id, src_user_id, dst_user_id, src_account_wallet_id, dst_account_wallet_id, src_account_id, dst_account_id, src_account_type, dst_account_type, final_dst_merchant_wallet_id, amount, currency, converted_amount, converted_currency, fx_quote_id, fx_rate, fx_mid_rate, fx_spread_amount, fee_amount, memo) VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)"
+	selecTractionQuery     = "SELECT id, pid, transaction_type, src_user_id, dst_user_id, src_account_wallet_id, dst_account_wallet_id, src_account_id, dst_account_id, src_account_type, dst_account_type, final_dst_merchant_wallet_id, amount, currency, converted_amount, converted_currency, COALESCE(fx_quote_id, ''), COALESCE(fx_rate, ''), COALESCE(fx_mid_rate, '') FROM transactions WHERE pid = ? AND transaction_type = ?"
 )
 
+const (
+	transactionTypeAuthorize = "AUTHORIZE"
+	transactionTypeIncrement = "INCREMENT"
+	transactionTypeCapture   = "CAPTURE"
+	transactionTypeVoid      = "VOID"
+	transactionTypeRefund    = "REFUND"
+	transactionTypeRelease   = "RELEASE"
+	transactionTypeExpire    = "EXPIRE"
+
+	transactionTypeDeposit            = "DEPOSIT"
+	transactionTypeWithdrawal         = "WITHDRAWAL"
+	transactionTypeWithdrawalReversal = "WITHDRAWAL_REVERSAL"
+	transactionTypeWithdrawalPayout   = "WITHDRAWAL_PAYOUT"
+)
+
+// errorDomain is the ErrorInfo domain of the rejections money_movement explains
+// with a reason, e.g. velocity limits and risk denials
+const errorDomain = "money_movement"
+
 type Implementation struct {
 	db *sql.DB
+	// store backs the payment flow so it can also run in memory
+	store store
+	bank  bank.Adapter
+	// riskEngine scores authorizations, nil allows all of them
+	riskEngine *risk.Engine
+	// authorizationTTL is how long an authorization can be raised before the
+	// expiry sweeper releases it, zero for no limit
+	authorizationTTL time.Duration
 	pb.UnimplementedMoneyMovementServiceServer
 }
 
-func NewMoneyMovementImplementation(db *sql.DB) *Implementation {
+func NewMoneyMovementImplementation(db *sql.DB, bankAdapter bank.Adapter, riskEngine *risk.Engine, authorizationTTL time.Duration) *Implementation {
 	return &Implementation{
-		db: db,
+		db:               db,
+		store:            mysqlStore{db},
+		bank:             bankAdapter,
+		riskEngine:       riskEngine,
+		authorizationTTL: authorizationTTL,
 	}
 }
 
 func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.AuthorizePayload) (*pb.AuthorizationResponse, error) {
-	if authorizePayload.GetCurrency() != "USD" {
-		return nil, status.Errorf(codes.InvalidArgument, "only USD currency is supported")
+	var decision riskDecision
+	response, err := retryOnConflict(ctx, func() (*pb.AuthorizationResponse, error) {
+		return impl.authorize(ctx, authorizePayload, &decision)
+	})
+
+	// The risk decision is logged on its own so that it is kept whether or not
+	// the authorization it was made for committed
+	if decision.decision.Outcome != "" {
+		logErr := impl.logRiskDecision(ctx, decision)
+		if logErr != nil {
+			log.Printf("Failed to log risk decision for customer %s: %v", decision.customerUserId, logErr)
+		}
+	}
+
+	return response, err
+}
+
+// authorize records the risk decision it made in logged, with the pid once the
+// authorization has committed.
+func (impl *Implementation) authorize(ctx context.Context, authorizePayload *pb.AuthorizePayload, logged *riskDecision) (*pb.AuthorizationResponse, error) {
+	// Forget the decision of an earlier attempt that did not commit
+	*logged = riskDecision{}
+
+	// Only the customer can spend from their wallet
+	err := callerFromContext(ctx).requireOwner(authorizePayload.GetCustomerWalletUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = currency.Validate(authorizePayload.GetCurrency())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// A split payment takes its merchants from the legs and records the first
+	// one as the payment's merchant
+	merchantWalletUserId := authorizePayload.GetMerchantWalletUserId()
+	if len(authorizePayload.GetLegs()) > 0 {
+		if merchantWalletUserId != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "a split payment takes its merchants from the legs")
+		}
+		if authorizePayload.GetFxQuoteId() != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "split payments cannot use an fx quote")
+		}
+		merchantWalletUserId = authorizePayload.GetLegs()[0].GetMerchantWalletUserId()
 	}
 
 	// Begin a transaction
-	tx, err := impl.db.Begin()
+	tx, err := impl.store.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay the stored response if this request was already processed
+	idempotencyKey := idempotencyKeyFromContext(ctx)
+	var storedResponse pb.AuthorizationResponse
+	fingerprint, replay, err := tx.lookupIdempotentResponse(callerFromContext(ctx).userId, idempotencyKey, "Authorize", authorizePayload, &storedResponse)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	if replay {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return &storedResponse, nil
+	}
+
+	merchantWallet, err := tx.fetchWallet(merchantWalletUserId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
 	}
 
-	merchantWallet, err := fetchWallet(tx, authorizePayload.MerchantWalletUserId)
+	custWallet, err := tx.fetchWallet(authorizePayload.CustomerWalletUserId)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -49,7 +156,16 @@ func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.
 		return nil, err
 	}
 
-	custWallet, err := fetchWallet(tx, authorizePayload.CustomerWalletUserId)
+	// Closed wallets can no longer take part in new payments
+	if merchantWallet.status != walletStatusActive || custWallet.status != walletStatusActive {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "wallet is closed")
+	}
+
+	legs, err := resolvePaymentLegs(tx, authorizePayload.GetLegs(), authorizePayload.Cents, authorizePayload.Currency)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -58,7 +174,16 @@ func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.
 		return nil, err
 	}
 
-	srcAccount, err := fetchAccount(tx, custWallet.ID, "DEFAULT")
+	merchantUserIds := []string{merchantWallet.UserId}
+	if len(legs) > 0 {
+		merchantUserIds = merchantUserIds[:0]
+		for _, leg := range legs {
+			merchantUserIds = append(merchantUserIds, leg.merchantUserId)
+		}
+	}
+
+	// Spending controls are checked against what the customer already authorized
+	limit, err := tx.fetchVelocityLimit(custWallet, authorizePayload.Currency)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -67,7 +192,7 @@ func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.
 		return nil, err
 	}
 
-	dstAccount, err := fetchAccount(tx, custWallet.ID, "PAYMENT")
+	usage, err := tx.fetchAuthorizationUsage(custWallet.UserId, authorizePayload.Currency)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -76,7 +201,83 @@ func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.
 		return nil, err
 	}
 
-	err = transfer(tx, srcAccount, dstAccount, authorizePayload.Cents)
+	err = checkVelocity(limit, usage, authorizePayload.Cents, authorizePayload.Currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Score the authorization before any funds move
+	decision := impl.riskEngine.Evaluate(risk.Input{
+		CustomerUserId:  custWallet.UserId,
+		MerchantUserIds: merchantUserIds,
+		Cents:           authorizePayload.Cents,
+		Currency:        authorizePayload.Currency,
+		WalletCreatedAt: time.Unix(custWallet.createdAt, 0),
+		Now:             time.Now(),
+	})
+	*logged = riskDecision{
+		customerUserId: custWallet.UserId,
+		merchantUserId: merchantWallet.UserId,
+		cents:          authorizePayload.Cents,
+		currency:       authorizePayload.Currency,
+		decision:       decision,
+	}
+	if decision.Outcome == risk.Deny {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, riskDeniedError(decision)
+	}
+
+	srcAccount, err := tx.fetchAccount(custWallet.ID, "DEFAULT", authorizePayload.Currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	dstAccount, err := tx.fetchAccount(custWallet.ID, "PAYMENT", authorizePayload.Currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// A quote converts the payment into the merchant's currency at capture time
+	var quote fxQuote
+	merchantCurrency := authorizePayload.Currency
+	if authorizePayload.GetFxQuoteId() != "" {
+		quote, err = tx.fetchFxQuote(authorizePayload.FxQuoteId)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, err
+		}
+		merchantCurrency = quote.dstCurrency
+	}
+
+	// The merchant must be able to receive the authorized currency at capture time
+	_, err = tx.fetchAccount(merchantWallet.ID, "INCOMING", merchantCurrency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.transfer(srcAccount, dstAccount, authorizePayload.Cents)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -86,7 +287,60 @@ func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.
 	}
 
 	pid := uuid.NewString()
-	err = createTransaction(tx, pid, srcAccount, dstAccount, custWallet, custWallet, merchantWallet, authorizePayload.Cents)
+	authorizeTransaction := newTransaction(pid, transactionTypeAuthorize, srcAccount, dstAccount, custWallet, custWallet, merchantWallet, authorizePayload.Cents)
+	if quote.quoteId != "" {
+		err = tx.useFxQuote(quote, pid, authorizePayload.Currency, authorizePayload.Cents)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, err
+		}
+		authorizeTransaction.fxQuoteId = quote.quoteId
+		authorizeTransaction.fxRate = quote.rate
+		authorizeTransaction.fxMidRate = quote.midRate
+		authorizeTransaction.convertedAmount = quote.dstCents
+		authorizeTransaction.convertedCurrency = quote.dstCurrency
+	}
+
+	err = tx.insertTransaction(authorizeTransaction)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.insertPayment(payment{
+		pid:              pid,
+		status:           paymentStatusAuthorized,
+		customerUserId:   custWallet.UserId,
+		merchantUserId:   merchantWallet.UserId,
+		customerWalletId: custWallet.ID,
+		merchantWalletId: merchantWallet.ID,
+		currency:         authorizePayload.Currency,
+		merchantCurrency: merchantCurrency,
+		authorizedCents:  authorizePayload.Cents,
+		riskDecision:     decision.Outcome,
+		riskReasons:      decision.Reasons,
+		legs:             legs,
+	})
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	response := &pb.AuthorizationResponse{
+		Pid:          pid,
+		RiskDecision: decision.Outcome,
+	}
+
+	err = tx.saveIdempotentResponse(callerFromContext(ctx).userId, idempotencyKey, "Authorize", fingerprint, response)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -100,21 +354,20 @@ func (impl *Implementation) Authorize(ctx context.Context, authorizePayload *pb.
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
 	}
+	logged.pid = pid
 
-	return &pb.AuthorizationResponse{
-		Pid: pid,
-	}, nil
+	return response, nil
 
 }
 
 func fetchWallet(tx *sql.Tx, userId string) (wallet, error) {
 	var w wallet
-	query := "SELECT id, user_id, wallet_type FROM wallets WHERE user_id = ?"
+	query := "SELECT id, user_id, wallet_type, status, UNIX_TIMESTAMP(created_at) FROM wallets WHERE user_id = ?"
 	stmt, err := tx.Prepare(query)
 	if err != nil {
 		return w, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
 	}
-	err = stmt.QueryRow(userId).Scan(&w.ID, &w.UserId, &w.walletType)
+	err = stmt.QueryRow(userId).Scan(&w.ID, &w.UserId, &w.walletType, &w.status, &w.createdAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return w, status.Errorf(codes.NotFound, "wallet not found for user: %s", userId)
@@ -126,12 +379,12 @@ func fetchWallet(tx *sql.Tx, userId string) (wallet, error) {
 
 func fetchWalletWithWalletId(tx *sql.Tx, walletId int32) (wallet, error) {
 	var w wallet
-	query := "SELECT id, user_id, wallet_type FROM wallets WHERE id = ?"
+	query := "SELECT id, user_id, wallet_type, status, UNIX_TIMESTAMP(created_at) FROM wallets WHERE id = ?"
 	stmt, err := tx.Prepare(query)
 	if err != nil {
 		return w, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
 	}
-	err = stmt.QueryRow(walletId).Scan(&w.ID, &w.UserId, &w.walletType)
+	err = stmt.QueryRow(walletId).Scan(&w.ID, &w.UserId, &w.walletType, &w.status, &w.createdAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return w, status.Errorf(codes.NotFound, "wallet not found for ID: %d", walletId)
@@ -141,62 +394,137 @@ func fetchWalletWithWalletId(tx *sql.Tx, walletId int32) (wallet, error) {
 	return w, nil
 }
 
-func fetchAccount(tx *sql.Tx, walletId int32, accountType string) (account, error) {
+func fetchAccount(tx *sql.Tx, walletId int32, accountType, currencyCode string) (account, error) {
 	var a account
-	query := "SELECT id, cents, account_type, wallet_id FROM accounts WHERE wallet_id = ? AND account_type = ?"
+	query := "SELECT id, cents, account_type, currency, wallet_id FROM accounts WHERE wallet_id = ? AND account_type = ? AND currency = ?"
 	stmt, err := tx.Prepare(query)
 	if err != nil {
 		return a, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
 	}
-	err = stmt.QueryRow(walletId, accountType).Scan(&a.ID, &a.cents, &a.accountType, &a.walletID)
+	err = stmt.QueryRow(walletId, accountType, currencyCode).Scan(&a.ID, &a.cents, &a.accountType, &a.currency, &a.walletID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return a, status.Errorf(codes.NotFound, "account not found for wallet ID: %d and type: %s", walletId, accountType)
+			return a, status.Errorf(codes.NotFound, "account not found for wallet ID: %d, type: %s and currency: %s", walletId, accountType, currencyCode)
 		}
 		return a, status.Errorf(codes.Internal, "failed to query account: %v", err)
 	}
 	return a, nil
 }
 
+// transfer moves amount between two accounts with relative updates, so the
+// balances read by fetchAccount are never written back. Rows are updated in
+// ascending id order so that concurrent transfers acquire row locks in the
+// same order. Every transfer is also posted to the journal.
 func transfer(tx *sql.Tx, srcAccount, dstAccount account, amount int64) error {
-	if srcAccount.cents < amount {
-		return status.Errorf(codes.FailedPrecondition, "insufficient funds in source account")
+	err := validateTransfer(srcAccount, dstAccount, amount)
+	if err != nil {
+		return err
+	}
+
+	if srcAccount.ID < dstAccount.ID {
+		err = debitAccount(tx, srcAccount, amount)
+		if err == nil {
+			err = creditAccount(tx, dstAccount, amount)
+		}
+	} else {
+		err = creditAccount(tx, dstAccount, amount)
+		if err == nil {
+			err = debitAccount(tx, srcAccount, amount)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return postJournalEntry(tx, srcAccount, dstAccount, amount)
+}
+
+func validateTransfer(srcAccount, dstAccount account, amount int64) error {
+	if amount <= 0 {
+		return status.Errorf(codes.InvalidArgument, "transfer amount must be positive")
+	}
+
+	if srcAccount.currency != dstAccount.currency {
+		return status.Errorf(codes.FailedPrecondition, "cannot transfer between %s and %s accounts", srcAccount.currency, dstAccount.currency)
 	}
 
-	// Deduct from source account
-	newSrcCents := srcAccount.cents - amount
-	stmt, err := tx.Prepare("UPDATE accounts SET cents = ? WHERE id = ?")
+	return nil
+}
+
+func debitAccount(tx *sql.Tx, srcAccount account, amount int64) error {
+	// Deduct from source account only if it holds enough funds, except from
+	// EXTERNAL_FUNDING, which mirrors the bank and may go negative
+	query, args := "UPDATE accounts SET cents = cents - ? WHERE id = ? AND cents >= ?", []any{amount, srcAccount.ID, amount}
+	if srcAccount.accountType == externalFundingAccountType {
+		query, args = "UPDATE accounts SET cents = cents - ? WHERE id = ?", []any{amount, srcAccount.ID}
+	}
+	stmt, err := tx.Prepare(query)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to update source account: %v", err)
 	}
 
-	_, err = stmt.Exec(newSrcCents, srcAccount.ID)
+	res, err := stmt.Exec(args...)
+	if err != nil {
+		return dbError("failed to update source account", err)
+	}
+
+	updated, err := res.RowsAffected()
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to update source account: %v", err)
 	}
+	if updated == 0 {
+		return status.Errorf(codes.FailedPrecondition, "insufficient funds in source account")
+	}
+
+	return nil
+}
 
+func creditAccount(tx *sql.Tx, dstAccount account, amount int64) error {
 	// Add to destination account
-	newDstCents := dstAccount.cents + amount
-	stmt, err = tx.Prepare("UPDATE accounts SET cents = ? WHERE id = ?")
+	stmt, err := tx.Prepare("UPDATE accounts SET cents = cents + ? WHERE id = ?")
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to update destination account: %v", err)
 	}
 
-	_, err = stmt.Exec(newDstCents, dstAccount.ID)
+	_, err = stmt.Exec(amount, dstAccount.ID)
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to update source account: %v", err)
+		return dbError("failed to update destination account", err)
 	}
 
 	return nil
 }
 
-func createTransaction(tx *sql.Tx, pid string, srcAccount, dstAccount account, srcWallet, dstWallet, finalDstWallet wallet, amount int64) error {
+func createTransaction(tx *sql.Tx, pid, transactionType string, srcAccount, dstAccount account, srcWallet, dstWallet, finalDstWallet wallet, amount int64) error {
+	return insertTransaction(tx, newTransaction(pid, transactionType, srcAccount, dstAccount, srcWallet, dstWallet, finalDstWallet, amount))
+}
+
+func newTransaction(pid, transactionType string, srcAccount, dstAccount account, srcWallet, dstWallet, finalDstWallet wallet, amount int64) transaction {
+	return transaction{
+		pid:                      pid,
+		transactionType:          transactionType,
+		srcUserId:                srcWallet.UserId,
+		dstUserId:                dstWallet.UserId,
+		srcAccountWalletId:       srcWallet.ID,
+		dstAccountWalletId:       dstWallet.ID,
+		srcAccountId:             srcAccount.ID,
+		dstAccountId:             dstAccount.ID,
+		srcAccountType:           srcAccount.accountType,
+		dstAccountType:           dstAccount.accountType,
+		finalDstMerchantWalletId: finalDstWallet.ID,
+		amount:                   amount,
+		currency:                 srcAccount.currency,
+		convertedAmount:          amount,
+		convertedCurrency:        dstAccount.currency,
+	}
+}
+
+func insertTransaction(tx *sql.Tx, t transaction) error {
 	stmt, err := tx.Prepare(insertTransactionQuery)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to prepare insert transaction statement: %v", err)
 	}
 
-	_, err = stmt.Exec(pid, srcWallet.UserId, dstWallet.UserId, srcWallet.ID, dstWallet.ID, srcAccount.ID, dstAccount.ID, srcAccount.accountType, dstAccount.accountType, finalDstWallet.ID, amount)
+	_, err = stmt.Exec(t.pid, t.transactionType, t.captureId, t.srcUserId, t.dstUserId, t.srcAccountWalletId, t.dstAccountWalletId, t.srcAccountId, t.dstAccountId, t.srcAccountType, t.dstAccountType, t.finalDstMerchantWalletId, t.amount, t.currency, t.convertedAmount, t.convertedCurrency, t.fxQuoteId, t.fxRate, t.fxMidRate, t.fxSpreadAmount, t.feeAmount, t.memo)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to insert transaction: %v", err)
 	}
@@ -204,22 +532,33 @@ func createTransaction(tx *sql.Tx, pid string, srcAccount, dstAccount account, s
 	return nil
 }
 
-func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.CapturePayload) (*emptypb.Empty, error) {
+func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.CapturePayload) (*pb.CaptureResponse, error) {
+	return retryOnConflict(ctx, func() (*pb.CaptureResponse, error) {
+		return impl.capture(ctx, capturePayload)
+	})
+}
+
+func (impl *Implementation) capture(ctx context.Context, capturePayload *pb.CapturePayload) (*pb.CaptureResponse, error) {
+	if capturePayload.GetCents() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "capture amount must not be negative")
+	}
+
 	//Begin a transaction
-	tx, err := impl.db.Begin()
+	tx, err := impl.store.begin(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+		return nil, err
 	}
 
-	authorizeTransaction, err := fetchTransaction(tx, capturePayload.Pid)
+	p, err := tx.fetchPayment(capturePayload.Pid)
 	if err != nil {
 		rollbackErr := tx.Rollback()
-		if err != nil {
+		if rollbackErr != nil {
 			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
 		}
 		return nil, err
 	}
-	srcAccount, err := fetchAccount(tx, authorizeTransaction.dstAccountWalletId, "PAYMENT")
+
+	err = callerFromContext(ctx).requirePaymentController(p)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -227,7 +566,11 @@ func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.Capt
 		}
 		return nil, err
 	}
-	dstMerchantAccount, err := fetchAccount(tx, authorizeTransaction.finalDstMerchantWalletId, "INCOMING")
+
+	// Replay the stored response if this request was already processed
+	idempotencyKey := idempotencyKeyFromContext(ctx)
+	var storedResponse pb.CaptureResponse
+	fingerprint, replay, err := tx.lookupIdempotentResponse(callerFromContext(ctx).userId, idempotencyKey, "Capture", capturePayload, &storedResponse)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -235,8 +578,15 @@ func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.Capt
 		}
 		return nil, err
 	}
+	if replay {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return &storedResponse, nil
+	}
 
-	err = transfer(tx, srcAccount, dstMerchantAccount, authorizeTransaction.amount)
+	authorizeTransaction, err := tx.fetchTransaction(capturePayload.Pid)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -245,7 +595,38 @@ func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.Capt
 		return nil, err
 	}
 
-	merchantWallet, err := fetchWalletWithWalletId(tx, authorizeTransaction.finalDstMerchantWalletId)
+	// A capture without an amount captures whatever is left on the authorization
+	remaining := p.remaining()
+	amount := capturePayload.GetCents()
+	if amount == 0 {
+		amount = remaining
+	}
+
+	if amount == 0 || amount > remaining {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "capture of %d exceeds remaining authorized amount %d for payment %s", amount, remaining, capturePayload.Pid)
+	}
+	if len(p.legs) > 0 && amount != remaining {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "split payment %s must be captured in full", capturePayload.Pid)
+	}
+	remaining -= amount
+
+	// Refunds of earlier captures carry over once the payment is captured
+	nextStatus := paymentStatusPartiallyCaptured
+	if remaining == 0 || capturePayload.GetFinalCapture() {
+		nextStatus = paymentStatusCaptured
+		if p.refundedCents > 0 {
+			nextStatus = paymentStatusPartiallyRefunded
+		}
+	}
+	err = p.transition(nextStatus)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -254,7 +635,7 @@ func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.Capt
 		return nil, err
 	}
 
-	customerWallet, err := fetchWallet(tx, authorizeTransaction.srcUserId)
+	srcAccount, err := tx.fetchAccount(authorizeTransaction.dstAccountWalletId, "PAYMENT", authorizeTransaction.currency)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -263,7 +644,96 @@ func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.Capt
 		return nil, err
 	}
 
-	err = createTransaction(tx, authorizeTransaction.pid, srcAccount, dstMerchantAccount, customerWallet, customerWallet, merchantWallet, authorizeTransaction.amount)
+	merchantWallet, err := tx.fetchWalletWithWalletId(authorizeTransaction.finalDstMerchantWalletId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	customerWallet, err := tx.fetchWallet(authorizeTransaction.srcUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Every merchant of a split payment is credited its leg under the same capture id
+	legs := []paymentLeg{{merchantWalletId: merchantWallet.ID, cents: amount}}
+	if len(p.legs) > 0 {
+		legs = p.legs
+	}
+
+	captureId := uuid.NewString()
+	var fee int64
+	var capturedLegs []producer.CaptureLeg
+	for _, leg := range legs {
+		captured, err := captureLeg(tx, authorizeTransaction, srcAccount, customerWallet, leg.merchantWalletId, captureId, leg.cents)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, err
+		}
+		capturedLegs = append(capturedLegs, captured)
+		fee += captured.Fee.Fee
+		p.merchantCapturedCents += captured.Fee.Gross
+	}
+	p.capturedCents += amount
+
+	// A final capture releases whatever was not captured back to the customer
+	if capturePayload.GetFinalCapture() && remaining > 0 {
+		err = releaseAuthorization(tx, authorizeTransaction, customerWallet, merchantWallet, remaining)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, err
+		}
+		p.releasedCents += remaining
+		remaining = 0
+	}
+
+	err = tx.updatePayment(p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Events are written in the same transaction so they are published only if the capture commits
+	err = tx.enqueueCaptureMessage(authorizeTransaction.pid, authorizeTransaction.srcUserId, amount, authorizeTransaction.currency, capturedLegs)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enqueue capture message: %v", err)
+	}
+
+	response := &pb.CaptureResponse{
+		CaptureId:      captureId,
+		RemainingCents: remaining,
+		FeeCents:       fee,
+	}
+	for _, leg := range capturedLegs {
+		response.Legs = append(response.Legs, &pb.CapturedLeg{
+			MerchantWalletUserId: leg.MerchantUserId,
+			Cents:                leg.Amount,
+			MerchantCents:        leg.Fee.Gross,
+			FeeCents:             leg.Fee.Fee,
+		})
+	}
+
+	err = tx.saveIdempotentResponse(callerFromContext(ctx).userId, idempotencyKey, "Capture", fingerprint, response)
 	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -278,10 +748,49 @@ func (impl *Implementation) Capture(ctx context.Context, capturePayload *pb.Capt
 		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
 	}
 
-	producer.SendCaptureMessage(authorizeTransaction.pid, authorizeTransaction.srcUserId, authorizeTransaction.amount)
+	return response, nil
 
-	return &emptypb.Empty{}, nil
+}
+
+// convertCapture returns the amount credited to the merchant for capturing
+// amount, and the spread kept by the house, at the rate locked on authorization.
+func convertCapture(authorizeTransaction transaction, amount int64) (int64, int64, error) {
+	if authorizeTransaction.fxQuoteId == "" {
+		return amount, 0, nil
+	}
+
+	convertedAmount, err := convertAtRate(amount, authorizeTransaction.fxRate, authorizeTransaction.currency, authorizeTransaction.convertedCurrency)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	midAmount, err := convertAtRate(amount, authorizeTransaction.fxMidRate, authorizeTransaction.currency, authorizeTransaction.convertedCurrency)
+	if err != nil {
+		return 0, 0, err
+	}
 
+	return convertedAmount, midAmount - convertedAmount, nil
+}
+
+// releaseAuthorization moves amount from the customer's PAYMENT account back
+// to DEFAULT and records it as a RELEASE transaction.
+func releaseAuthorization(tx unitOfWork, authorizeTransaction transaction, customerWallet, merchantWallet wallet, amount int64) error {
+	srcAccount, err := tx.fetchAccount(authorizeTransaction.dstAccountWalletId, "PAYMENT", authorizeTransaction.currency)
+	if err != nil {
+		return err
+	}
+
+	dstAccount, err := tx.fetchAccount(authorizeTransaction.srcAccountWalletId, "DEFAULT", authorizeTransaction.currency)
+	if err != nil {
+		return err
+	}
+
+	err = tx.transfer(srcAccount, dstAccount, amount)
+	if err != nil {
+		return err
+	}
+
+	return tx.insertTransaction(newTransaction(authorizeTransaction.pid, transactionTypeRelease, srcAccount, dstAccount, customerWallet, customerWallet, merchantWallet, amount))
 }
 
 func fetchTransaction(tx *sql.Tx, pid string) (transaction, error) {
@@ -290,12 +799,358 @@ func fetchTransaction(tx *sql.Tx, pid string) (transaction, error) {
 	if err != nil {
 		return t, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
 	}
-	err = stmt.QueryRow(pid).Scan(&t.ID, &t.pid, &t.srcUserId, &t.dstUserId, &t.srcAccountWalletId, &t.dstAccountWalletId, &t.srcAccountId, &t.dstAccountId, &t.srcAccountType, &t.dstAccountType, &t.finalDstMerchantWalletId, &t.amount)
+	err = stmt.QueryRow(pid, transactionTypeAuthorize).Scan(&t.ID, &t.pid, &t.transactionType, &t.srcUserId, &t.dstUserId, &t.srcAccountWalletId, &t.dstAccountWalletId, &t.srcAccountId, &t.dstAccountId, &t.srcAccountType, &t.dstAccountType, &t.finalDstMerchantWalletId, &t.amount, &t.currency, &t.convertedAmount, &t.convertedCurrency, &t.fxQuoteId, &t.fxRate, &t.fxMidRate)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return t, status.Errorf(codes.NotFound, "transaction not found for pid: %s", pid)
 		}
-		return t, status.Errorf(codes.Internal, "failed to query transaction: %v", err)
+		return t, dbError("failed to query transaction", err)
 	}
 	return t, nil
 }
+
+func (impl *Implementation) Void(ctx context.Context, voidPayload *pb.VoidPayload) (*emptypb.Empty, error) {
+	return retryOnConflict(ctx, func() (*emptypb.Empty, error) {
+		return impl.void(ctx, voidPayload)
+	})
+}
+
+func (impl *Implementation) void(ctx context.Context, voidPayload *pb.VoidPayload) (*emptypb.Empty, error) {
+	//Begin a transaction
+	tx, err := impl.store.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := tx.fetchPayment(voidPayload.Pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = callerFromContext(ctx).requirePaymentController(p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	authorizeTransaction, err := tx.fetchTransaction(voidPayload.Pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Only a payment that still holds funds can be voided. Voiding a partially
+	// captured payment releases the rest and keeps what was captured.
+	remaining := p.remaining()
+	nextStatus := paymentStatusVoided
+	if p.status == paymentStatusPartiallyCaptured {
+		nextStatus = p.capturedStatus()
+	}
+	err = p.transition(nextStatus)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	srcAccount, err := tx.fetchAccount(authorizeTransaction.dstAccountWalletId, "PAYMENT", authorizeTransaction.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	dstAccount, err := tx.fetchAccount(authorizeTransaction.srcAccountWalletId, "DEFAULT", authorizeTransaction.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Move the held amount back to the customer's DEFAULT account
+	err = tx.transfer(srcAccount, dstAccount, remaining)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	customerWallet, err := tx.fetchWallet(authorizeTransaction.srcUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	merchantWallet, err := tx.fetchWalletWithWalletId(authorizeTransaction.finalDstMerchantWalletId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.insertTransaction(newTransaction(authorizeTransaction.pid, transactionTypeVoid, srcAccount, dstAccount, customerWallet, customerWallet, merchantWallet, remaining))
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	p.releasedCents += remaining
+	err = tx.updatePayment(p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (impl *Implementation) Refund(ctx context.Context, refundPayload *pb.RefundPayload) (*emptypb.Empty, error) {
+	return retryOnConflict(ctx, func() (*emptypb.Empty, error) {
+		return impl.refund(ctx, refundPayload)
+	})
+}
+
+func (impl *Implementation) refund(ctx context.Context, refundPayload *pb.RefundPayload) (*emptypb.Empty, error) {
+	if refundPayload.GetCents() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "refund amount must be positive")
+	}
+
+	//Begin a transaction
+	tx, err := impl.store.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := tx.fetchPayment(refundPayload.Pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	authorizeTransaction, err := tx.fetchTransaction(refundPayload.Pid)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Refunds are issued by the merchant, not requested by the customer. A
+	// split payment is refunded leg by leg, each from its merchant's INCOMING
+	// account and within what that leg was captured for.
+	c := callerFromContext(ctx)
+	merchantWalletId := authorizeTransaction.finalDstMerchantWalletId
+	alreadyRefunded := p.refundedCents
+	refundable := p.merchantCapturedCents - alreadyRefunded
+	leg := -1
+	if len(p.legs) > 0 {
+		leg, err = refundLeg(c, p, refundPayload.GetMerchantWalletUserId())
+		if err == nil {
+			merchantWalletId = p.legs[leg].merchantWalletId
+			alreadyRefunded = p.legs[leg].refundedCents
+			refundable = p.legs[leg].cents - alreadyRefunded
+		}
+	} else if refundPayload.GetMerchantWalletUserId() != "" && refundPayload.GetMerchantWalletUserId() != p.merchantUserId {
+		err = status.Errorf(codes.InvalidArgument, "merchant %s is not the merchant of payment %s", refundPayload.GetMerchantWalletUserId(), p.pid)
+	} else {
+		err = c.requireMerchant(p.merchantUserId)
+	}
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Partial refunds are allowed as long as their total stays within the
+	// captured amount, expressed in the merchant's currency
+	if refundPayload.Cents > refundable {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "refund of %d exceeds refundable amount %d for payment %s", refundPayload.Cents, refundable, refundPayload.Pid)
+	}
+	p.refundedCents += refundPayload.Cents
+	if leg >= 0 {
+		p.legs[leg].refundedCents += refundPayload.Cents
+	}
+
+	// A payment that still holds funds stays open for further captures
+	nextStatus := paymentStatusPartiallyRefunded
+	if p.refundedCents == p.merchantCapturedCents {
+		nextStatus = paymentStatusRefunded
+	}
+	if p.status == paymentStatusPartiallyCaptured {
+		nextStatus = paymentStatusPartiallyCaptured
+	}
+	err = p.transition(nextStatus)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	srcMerchantAccount, err := tx.fetchAccount(merchantWalletId, "INCOMING", authorizeTransaction.convertedCurrency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	dstAccount, err := tx.fetchAccount(authorizeTransaction.srcAccountWalletId, "DEFAULT", authorizeTransaction.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	merchantWallet, err := tx.fetchWalletWithWalletId(merchantWalletId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	customerWallet, err := tx.fetchWallet(authorizeTransaction.srcUserId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// The merchant was credited net of its fee, so the house returns the fee's
+	// share of the refund and the merchant only pays back its net
+	feeReturned, err := returnFee(tx, p.pid, srcMerchantAccount, alreadyRefunded, refundPayload.Cents)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	srcMerchantAccount.cents += feeReturned
+
+	err = reverseSettlement(tx, p.pid, merchantWallet, srcMerchantAccount, refundPayload.Cents)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Foreign-exchange refunds are converted back at the rate locked on authorization
+	creditAmount := refundPayload.Cents
+	if authorizeTransaction.fxQuoteId == "" {
+		err = tx.transfer(srcMerchantAccount, dstAccount, refundPayload.Cents)
+	} else {
+		creditAmount, err = convertBack(refundPayload.Cents, authorizeTransaction.fxRate, authorizeTransaction.currency, authorizeTransaction.convertedCurrency)
+		if err == nil {
+			err = fxTransfer(tx, srcMerchantAccount, dstAccount, refundPayload.Cents, creditAmount, 0)
+		}
+	}
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	refundTransaction := newTransaction(authorizeTransaction.pid, transactionTypeRefund, srcMerchantAccount, dstAccount, merchantWallet, customerWallet, merchantWallet, refundPayload.Cents)
+	refundTransaction.convertedAmount = creditAmount
+	refundTransaction.fxQuoteId = authorizeTransaction.fxQuoteId
+	refundTransaction.fxRate = authorizeTransaction.fxRate
+	refundTransaction.feeAmount = feeReturned
+	refundTransaction.memo = refundPayload.Reason
+	err = tx.insertTransaction(refundTransaction)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.updatePayment(p)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = tx.enqueueRefundMessage(authorizeTransaction.pid, authorizeTransaction.srcUserId, creditAmount, authorizeTransaction.currency, merchantWallet.UserId, producer.CaptureFee{
+		Gross:    refundPayload.Cents,
+		Fee:      feeReturned,
+		Net:      refundPayload.Cents - feeReturned,
+		Currency: srcMerchantAccount.currency,
+	})
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enqueue refund message: %v", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
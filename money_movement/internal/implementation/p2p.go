@@ -0,0 +1,211 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/MikePham0630/gomicro/internal/currency"
+	"github.com/MikePham0630/gomicro/internal/producer"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const transactionTypeP2PTransfer = "P2P_TRANSFER"
+
+// sumSentTodayQuery is a locking read so that concurrent transfers from the
+// same sender cannot both pass the daily transfer limit.
+const sumSentTodayQuery = "SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE src_user_id = ? AND transaction_type = ? AND currency = ? AND created_at >= CURDATE() FOR UPDATE"
+
+func (impl *Implementation) Transfer(ctx context.Context, transferPayload *pb.TransferPayload) (*pb.TransferResponse, error) {
+	return retryOnConflict(ctx, func() (*pb.TransferResponse, error) {
+		return impl.p2pTransfer(ctx, transferPayload)
+	})
+}
+
+func (impl *Implementation) p2pTransfer(ctx context.Context, transferPayload *pb.TransferPayload) (*pb.TransferResponse, error) {
+	if err := callerFromContext(ctx).requireOwner(transferPayload.GetFromUserId()); err != nil {
+		return nil, err
+	}
+
+	if transferPayload.GetCents() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be positive")
+	}
+
+	if transferPayload.GetFromUserId() == transferPayload.GetToUserId() {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot transfer to the same user")
+	}
+
+	currencyCode := transferPayload.GetCurrency()
+	if currencyCode == "" {
+		currencyCode = defaultWalletCurrency
+	}
+	if err := currency.Validate(currencyCode); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	// Replay the stored response if this request was already processed
+	idempotencyKey := idempotencyKeyFromContext(ctx)
+	var storedResponse pb.TransferResponse
+	fingerprint, replay, err := lookupIdempotentResponse(tx, callerFromContext(ctx).userId, idempotencyKey, "Transfer", transferPayload, &storedResponse)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	if replay {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return &storedResponse, nil
+	}
+
+	fromWallet, err := fetchWallet(tx, transferPayload.GetFromUserId())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	toWallet, err := fetchWallet(tx, transferPayload.GetToUserId())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	// Only active customer wallets can send or receive transfers
+	for _, w := range []wallet{fromWallet, toWallet} {
+		if w.walletType != walletTypeCustomer || w.status != walletStatusActive {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, status.Errorf(codes.FailedPrecondition, "wallet %d cannot take part in transfers", w.ID)
+		}
+	}
+
+	sentToday, err := sumSentToday(tx, fromWallet.UserId, currencyCode)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	limit, err := fetchVelocityLimit(tx, fromWallet, currencyCode)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	if limit.maxDailyTransferCents > 0 && sentToday+transferPayload.GetCents() > limit.maxDailyTransferCents {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, velocityError(reasonDailyTransferLimit, "transfer of %d %s exceeds the daily transfer limit of %d", transferPayload.GetCents(), currencyCode, limit.maxDailyTransferCents, sentToday)
+	}
+
+	srcAccount, err := fetchAccount(tx, fromWallet.ID, "DEFAULT", currencyCode)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	dstAccount, err := fetchAccount(tx, toWallet.ID, "DEFAULT", currencyCode)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = transfer(tx, srcAccount, dstAccount, transferPayload.GetCents())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	transferId := uuid.NewString()
+	p2pTransaction := newTransaction(transferId, transactionTypeP2PTransfer, srcAccount, dstAccount, fromWallet, toWallet, toWallet, transferPayload.GetCents())
+	p2pTransaction.memo = transferPayload.GetMemo()
+	err = insertTransaction(tx, p2pTransaction)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	err = producer.EnqueueTransferMessages(tx, transferId, fromWallet.UserId, toWallet.UserId, transferPayload.GetCents(), currencyCode, transferPayload.GetMemo())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enqueue transfer messages: %v", err)
+	}
+
+	response := &pb.TransferResponse{
+		TransferId: transferId,
+	}
+
+	err = saveIdempotentResponse(tx, callerFromContext(ctx).userId, idempotencyKey, "Transfer", fingerprint, response)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return response, nil
+}
+
+func sumSentToday(tx *sql.Tx, userId, currencyCode string) (int64, error) {
+	stmt, err := tx.Prepare(sumSentTodayQuery)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	var sum int64
+	err = stmt.QueryRow(userId, transactionTypeP2PTransfer, currencyCode).Scan(&sum)
+	if err != nil {
+		return 0, dbError("failed to sum transfers", err)
+	}
+
+	return sum, nil
+}
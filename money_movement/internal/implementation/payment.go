@@ -0,0 +1,286 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	paymentStatusAuthorized        = "AUTHORIZED"
+	paymentStatusPartiallyCaptured = "PARTIALLY_CAPTURED"
+	paymentStatusCaptured          = "CAPTURED"
+	paymentStatusVoided            = "VOIDED"
+	paymentStatusExpired           = "EXPIRED"
+	paymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	paymentStatusRefunded          = "REFUNDED"
+)
+
+const (
+	insertPaymentQuery = "INSERT INTO payments (pid, status, customer_user_id, merchant_user_id, customer_wallet_id, merchant_wallet_id, currency, merchant_currency, authorized_cents, risk_decision, risk_reasons) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	selectPaymentQuery = "SELECT pid, status, customer_user_id, merchant_user_id, customer_wallet_id, merchant_wallet_id, currency, merchant_currency, authorized_cents, captured_cents, merchant_captured_cents, released_cents, refunded_cents, risk_decision, risk_reasons, UNIX_TIMESTAMP(created_at), UNIX_TIMESTAMP(updated_at) FROM payments WHERE pid = ?"
+	updatePaymentQuery = "UPDATE payments SET status = ?, authorized_cents = ?, captured_cents = ?, merchant_captured_cents = ?, released_cents = ?, refunded_cents = ? WHERE pid = ?"
+)
+
+const (
+	insertPaymentLegQuery  = "INSERT INTO payment_legs (pid, leg, merchant_user_id, merchant_wallet_id, cents) VALUES (?, ?, ?, ?, ?)"
+	selectPaymentLegsQuery = "SELECT merchant_user_id, merchant_wallet_id, cents, refunded_cents FROM payment_legs WHERE pid = ? ORDER BY leg"
+	updatePaymentLegQuery  = "UPDATE payment_legs SET refunded_cents = ? WHERE pid = ? AND leg = ?"
+)
+
+// paymentTransitions lists the statuses a payment may move to from each status.
+// VOIDED, EXPIRED and REFUNDED are terminal. A partially captured payment can
+// be refunded while it still holds funds and stays PARTIALLY_CAPTURED until
+// the rest is captured, voided or expires; it then moves straight to the
+// refund status matching what was refunded so far.
+var paymentTransitions = map[string][]string{
+	paymentStatusAuthorized:        {paymentStatusPartiallyCaptured, paymentStatusCaptured, paymentStatusVoided, paymentStatusExpired},
+	paymentStatusPartiallyCaptured: {paymentStatusPartiallyCaptured, paymentStatusCaptured, paymentStatusPartiallyRefunded, paymentStatusRefunded},
+	paymentStatusCaptured:          {paymentStatusPartiallyRefunded, paymentStatusRefunded},
+	paymentStatusPartiallyRefunded: {paymentStatusPartiallyRefunded, paymentStatusRefunded},
+}
+
+func isPaymentStatus(s string) bool {
+	switch s {
+	case paymentStatusAuthorized, paymentStatusPartiallyCaptured, paymentStatusCaptured, paymentStatusVoided, paymentStatusExpired, paymentStatusPartiallyRefunded, paymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
+type payment struct {
+	pid                   string
+	status                string
+	customerUserId        string
+	merchantUserId        string
+	customerWalletId      int32
+	merchantWalletId      int32
+	currency              string
+	merchantCurrency      string
+	authorizedCents       int64
+	capturedCents         int64
+	merchantCapturedCents int64
+	releasedCents         int64
+	refundedCents         int64
+	riskDecision          string
+	riskReasons           []string
+	createdAt             int64
+	updatedAt             int64
+
+	// legs splits a payment across merchants; the first leg's merchant is
+	// also the payment's merchant. Payments to one merchant have no legs.
+	legs []paymentLeg
+}
+
+type paymentLeg struct {
+	merchantUserId   string
+	merchantWalletId int32
+	cents            int64
+	refundedCents    int64
+}
+
+// remaining returns the part of the authorization still held in the
+// customer's PAYMENT account.
+func (p payment) remaining() int64 {
+	return p.authorizedCents - p.capturedCents - p.releasedCents
+}
+
+// capturedStatus is the status of a payment that holds no more funds and was
+// captured for merchantCapturedCents: CAPTURED, or the refund status matching
+// what was refunded while it was partially captured.
+func (p payment) capturedStatus() string {
+	switch {
+	case p.refundedCents == 0:
+		return paymentStatusCaptured
+	case p.refundedCents >= p.merchantCapturedCents:
+		return paymentStatusRefunded
+	default:
+		return paymentStatusPartiallyRefunded
+	}
+}
+
+// transition moves the payment to next, rejecting transitions the lifecycle
+// does not allow, such as capturing a voided payment.
+func (p *payment) transition(next string) error {
+	for _, allowed := range paymentTransitions[p.status] {
+		if allowed == next {
+			p.status = next
+			return nil
+		}
+	}
+	return status.Errorf(codes.FailedPrecondition, "payment %s cannot move from %s to %s", p.pid, p.status, next)
+}
+
+func insertPayment(tx *sql.Tx, p payment) error {
+	stmt, err := tx.Prepare(insertPaymentQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert payment statement: %v", err)
+	}
+
+	_, err = stmt.Exec(p.pid, p.status, p.customerUserId, p.merchantUserId, p.customerWalletId, p.merchantWalletId, p.currency, p.merchantCurrency, p.authorizedCents, p.riskDecision, joinRiskReasons(p.riskReasons))
+	if err != nil {
+		return dbError("failed to insert payment", err)
+	}
+
+	if len(p.legs) == 0 {
+		return nil
+	}
+
+	stmt, err = tx.Prepare(insertPaymentLegQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert payment leg statement: %v", err)
+	}
+
+	for i, leg := range p.legs {
+		_, err = stmt.Exec(p.pid, i, leg.merchantUserId, leg.merchantWalletId, leg.cents)
+		if err != nil {
+			return dbError("failed to insert payment leg", err)
+		}
+	}
+
+	return nil
+}
+
+// fetchPayment locks the payment row so that operations on the same pid are
+// serialized for the rest of tx.
+func fetchPayment(tx *sql.Tx, pid string) (payment, error) {
+	var p payment
+	stmt, err := tx.Prepare(selectPaymentQuery + " FOR UPDATE")
+	if err != nil {
+		return p, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = scanPayment(stmt.QueryRow(pid), &p)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return p, status.Errorf(codes.NotFound, "payment not found for pid: %s", pid)
+		}
+		return p, dbError("failed to query payment", err)
+	}
+
+	stmt, err = tx.Prepare(selectPaymentLegsQuery)
+	if err != nil {
+		return p, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	p.legs, err = queryPaymentLegs(stmt, pid)
+	if err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func updatePayment(tx *sql.Tx, p payment) error {
+	stmt, err := tx.Prepare(updatePaymentQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare update payment statement: %v", err)
+	}
+
+	_, err = stmt.Exec(p.status, p.authorizedCents, p.capturedCents, p.merchantCapturedCents, p.releasedCents, p.refundedCents, p.pid)
+	if err != nil {
+		return dbError("failed to update payment", err)
+	}
+
+	if len(p.legs) == 0 {
+		return nil
+	}
+
+	stmt, err = tx.Prepare(updatePaymentLegQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare update payment leg statement: %v", err)
+	}
+
+	for i, leg := range p.legs {
+		_, err = stmt.Exec(leg.refundedCents, p.pid, i)
+		if err != nil {
+			return dbError("failed to update payment leg", err)
+		}
+	}
+
+	return nil
+}
+
+func scanPayment(row *sql.Row, p *payment) error {
+	var riskReasons string
+	err := row.Scan(&p.pid, &p.status, &p.customerUserId, &p.merchantUserId, &p.customerWalletId, &p.merchantWalletId, &p.currency, &p.merchantCurrency, &p.authorizedCents, &p.capturedCents, &p.merchantCapturedCents, &p.releasedCents, &p.refundedCents, &p.riskDecision, &riskReasons, &p.createdAt, &p.updatedAt)
+	if err != nil {
+		return err
+	}
+	p.riskReasons = splitRiskReasons(riskReasons)
+	return nil
+}
+
+func queryPaymentLegs(stmt *sql.Stmt, pid string) ([]paymentLeg, error) {
+	rows, err := stmt.Query(pid)
+	if err != nil {
+		return nil, dbError("failed to query payment legs", err)
+	}
+	defer rows.Close()
+
+	var legs []paymentLeg
+	for rows.Next() {
+		var leg paymentLeg
+		err := rows.Scan(&leg.merchantUserId, &leg.merchantWalletId, &leg.cents, &leg.refundedCents)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan payment leg: %v", err)
+		}
+		legs = append(legs, leg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query payment legs: %v", err)
+	}
+
+	return legs, nil
+}
+
+func (impl *Implementation) GetPayment(ctx context.Context, getPaymentPayload *pb.GetPaymentPayload) (*pb.Payment, error) {
+	var p payment
+	err := scanPayment(impl.db.QueryRowContext(ctx, selectPaymentQuery, getPaymentPayload.GetPid()), &p)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "payment not found for pid: %s", getPaymentPayload.GetPid())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to query payment: %v", err)
+	}
+
+	stmt, err := impl.db.PrepareContext(ctx, selectPaymentLegsQuery)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	defer stmt.Close()
+	p.legs, err = queryPaymentLegs(stmt, p.pid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = callerFromContext(ctx).requirePaymentParty(p)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]*pb.PaymentLeg, 0, len(p.legs))
+	for _, leg := range p.legs {
+		legs = append(legs, &pb.PaymentLeg{MerchantWalletUserId: leg.merchantUserId, Cents: leg.cents, RefundedCents: leg.refundedCents})
+	}
+
+	return &pb.Payment{
+		Pid:                   p.pid,
+		Status:                p.status,
+		CustomerWalletUserId:  p.customerUserId,
+		MerchantWalletUserId:  p.merchantUserId,
+		Currency:              p.currency,
+		MerchantCurrency:      p.merchantCurrency,
+		AuthorizedCents:       p.authorizedCents,
+		CapturedCents:         p.capturedCents,
+		MerchantCapturedCents: p.merchantCapturedCents,
+		ReleasedCents:         p.releasedCents,
+		RefundedCents:         p.refundedCents,
+		RiskDecision:          p.riskDecision,
+		RiskReasons:           p.riskReasons,
+		Legs:                  legs,
+		CreatedAt:             p.createdAt,
+		UpdatedAt:             p.updatedAt,
+	}, nil
+}
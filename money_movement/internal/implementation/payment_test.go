@@ -0,0 +1,946 @@
+package mm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/risk"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testEnv runs the payment flow against a memoryStore seeded with a customer
+// holding customerCents USD, a merchant and the house wallet.
+type testEnv struct {
+	t        *testing.T
+	store    *memoryStore
+	impl     *Implementation
+	customer wallet
+	merchant wallet
+	house    wallet
+}
+
+func newTestEnv(t *testing.T, customerCents int64) *testEnv {
+	t.Helper()
+	s := newMemoryStore()
+	e := &testEnv{t: t, store: s, impl: &Implementation{store: s}}
+
+	e.customer = s.addWallet("customer", walletTypeCustomer)
+	s.addAccount(e.customer.ID, "DEFAULT", "USD", customerCents)
+	s.addAccount(e.customer.ID, "PAYMENT", "USD", 0)
+
+	e.merchant = e.addMerchant("merchant")
+
+	e.house = s.addWallet(houseWalletUserId, "HOUSE")
+	s.addAccount(e.house.ID, revenueAccountType, "USD", 0)
+
+	return e
+}
+
+func (e *testEnv) addMerchant(userId string) wallet {
+	w := e.store.addWallet(userId, walletTypeMerchant)
+	e.store.addAccount(w.ID, "INCOMING", "USD", 0)
+	e.store.addAccount(w.ID, "SETTLED", "USD", 0)
+	return w
+}
+
+// balance returns the committed balance of one of w's accounts.
+func (e *testEnv) balance(w wallet, accountType, currencyCode string) int64 {
+	e.t.Helper()
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	for _, a := range e.store.state.accounts {
+		if a.walletID == w.ID && a.accountType == accountType && a.currency == currencyCode {
+			return a.cents
+		}
+	}
+	e.t.Fatalf("wallet %s has no %s %s account", w.UserId, accountType, currencyCode)
+	return 0
+}
+
+// snapshot returns every committed balance by account id.
+func (e *testEnv) snapshot() map[int32]int64 {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	balances := make(map[int32]int64, len(e.store.state.accounts))
+	for id, a := range e.store.state.accounts {
+		balances[id] = a.cents
+	}
+	return balances
+}
+
+// committed returns the number of transaction rows and payments committed so far.
+func (e *testEnv) committed() (int, int) {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	return len(e.store.state.transactions), len(e.store.state.payments)
+}
+
+func (e *testEnv) payment(pid string) payment {
+	e.t.Helper()
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	p, ok := e.store.state.payments[pid]
+	if !ok {
+		e.t.Fatalf("payment %s not found", pid)
+	}
+	return p
+}
+
+func (e *testEnv) closeWallet(w wallet) {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	w = e.store.state.wallets[w.ID]
+	w.status = walletStatusClosed
+	e.store.state.wallets[w.ID] = w
+}
+
+// authorize authorizes cents from the customer to the merchant and fails the
+// test if it is rejected.
+func (e *testEnv) authorize(cents int64) string {
+	e.t.Helper()
+	res, err := e.impl.Authorize(asCaller(e.customer.UserId, roleCustomer), &pb.AuthorizePayload{
+		CustomerWalletUserId: e.customer.UserId,
+		MerchantWalletUserId: e.merchant.UserId,
+		Cents:                cents,
+		Currency:             "USD",
+	})
+	if err != nil {
+		e.t.Fatalf("Authorize: %v", err)
+	}
+	return res.Pid
+}
+
+// authorizeSplit authorizes 300 cents split between the merchant and other.
+func (e *testEnv) authorizeSplit(other wallet) string {
+	e.t.Helper()
+	res, err := e.impl.Authorize(asCaller(e.customer.UserId, roleCustomer), &pb.AuthorizePayload{
+		CustomerWalletUserId: e.customer.UserId,
+		Cents:                300,
+		Currency:             "USD",
+		Legs: []*pb.PaymentLeg{
+			{MerchantWalletUserId: e.merchant.UserId, Cents: 200},
+			{MerchantWalletUserId: other.UserId, Cents: 100},
+		},
+	})
+	if err != nil {
+		e.t.Fatalf("Authorize: %v", err)
+	}
+	return res.Pid
+}
+
+func asCaller(userId, role string) context.Context {
+	return context.WithValue(context.Background(), callerContextKey{}, caller{userId: userId, role: role})
+}
+
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(idempotencyKeyHeader, key))
+}
+
+func requireCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if status.Code(err) != want {
+		t.Fatalf("got error %v, want code %s", err, want)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		cents    int64
+		setup    func(e *testEnv, payload *pb.AuthorizePayload)
+		wantCode codes.Code
+	}{
+		{
+			name:   "moves funds from DEFAULT to PAYMENT",
+			caller: "customer",
+			cents:  300,
+		},
+		{
+			name:     "insufficient funds",
+			caller:   "customer",
+			cents:    1001,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "only the customer can spend from the wallet",
+			caller:   "merchant",
+			cents:    300,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "closed merchant wallet",
+			caller: "customer",
+			cents:  300,
+			setup: func(e *testEnv, _ *pb.AuthorizePayload) {
+				e.closeWallet(e.merchant)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "velocity limit",
+			caller: "customer",
+			cents:  300,
+			setup: func(e *testEnv, _ *pb.AuthorizePayload) {
+				e.store.setVelocityLimit(walletTypeCustomer, 0, "USD", velocityLimit{maxSingleCents: 200})
+			},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			// The quote is only checked after the customer's funds have
+			// moved to PAYMENT, so the transfer has to be rolled back
+			name:   "rolls back funds already moved",
+			caller: "customer",
+			cents:  300,
+			setup: func(e *testEnv, payload *pb.AuthorizePayload) {
+				e.store.addAccount(e.merchant.ID, "INCOMING", "EUR", 0)
+				e.store.addFxQuote(fxQuote{quoteId: "quote", srcCurrency: "USD", dstCurrency: "EUR", srcCents: 500, dstCents: 450, rate: "0.9", midRate: "0.92"}, time.Now().Add(time.Minute))
+				payload.FxQuoteId = "quote"
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t, 1000)
+			payload := &pb.AuthorizePayload{
+				CustomerWalletUserId: e.customer.UserId,
+				MerchantWalletUserId: e.merchant.UserId,
+				Cents:                tt.cents,
+				Currency:             "USD",
+			}
+			if tt.setup != nil {
+				tt.setup(e, payload)
+			}
+			before := e.snapshot()
+			transactionsBefore, paymentsBefore := e.committed()
+
+			res, err := e.impl.Authorize(asCaller(tt.caller, roleCustomer), payload)
+			requireCode(t, err, tt.wantCode)
+
+			if tt.wantCode != codes.OK {
+				for id, cents := range e.snapshot() {
+					if before[id] != cents {
+						t.Errorf("account %d changed from %d to %d", id, before[id], cents)
+					}
+				}
+				transactions, payments := e.committed()
+				if transactions != transactionsBefore || payments != paymentsBefore {
+					t.Errorf("failed authorization left %d transactions and %d payments behind", transactions-transactionsBefore, payments-paymentsBefore)
+				}
+				return
+			}
+
+			if got := e.balance(e.customer, "DEFAULT", "USD"); got != 1000-tt.cents {
+				t.Errorf("DEFAULT = %d, want %d", got, 1000-tt.cents)
+			}
+			if got := e.balance(e.customer, "PAYMENT", "USD"); got != tt.cents {
+				t.Errorf("PAYMENT = %d, want %d", got, tt.cents)
+			}
+			p := e.payment(res.Pid)
+			if p.status != paymentStatusAuthorized || p.authorizedCents != tt.cents {
+				t.Errorf("payment is %s for %d, want %s for %d", p.status, p.authorizedCents, paymentStatusAuthorized, tt.cents)
+			}
+		})
+	}
+}
+
+func TestAuthorizeIdempotentReplay(t *testing.T) {
+	e := newTestEnv(t, 1000)
+	ctx := withIdempotencyKey(asCaller(e.customer.UserId, roleCustomer), "key")
+	payload := &pb.AuthorizePayload{
+		CustomerWalletUserId: e.customer.UserId,
+		MerchantWalletUserId: e.merchant.UserId,
+		Cents:                300,
+		Currency:             "USD",
+	}
+
+	first, err := e.impl.Authorize(ctx, payload)
+	requireCode(t, err, codes.OK)
+	second, err := e.impl.Authorize(ctx, payload)
+	requireCode(t, err, codes.OK)
+
+	if first.Pid != second.Pid {
+		t.Errorf("replay returned pid %s, want %s", second.Pid, first.Pid)
+	}
+	if got := e.balance(e.customer, "PAYMENT", "USD"); got != 300 {
+		t.Errorf("PAYMENT = %d after replay, want 300", got)
+	}
+
+	payload.Cents = 400
+	_, err = e.impl.Authorize(ctx, payload)
+	requireCode(t, err, codes.InvalidArgument)
+}
+
+func TestAuthorizeLogsRiskDecision(t *testing.T) {
+	rules, err := risk.Parse([]byte(`{"rules": [{"type": "amount_above", "outcome": "DENY", "reason": "TOO_LARGE", "params": {"currency": "USD", "cents": 500}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := newTestEnv(t, 1000)
+	e.impl.riskEngine = rules
+	ctx := asCaller(e.customer.UserId, roleCustomer)
+	authorize := func(cents int64, quoteId string) error {
+		_, err := e.impl.Authorize(ctx, &pb.AuthorizePayload{
+			CustomerWalletUserId: e.customer.UserId,
+			MerchantWalletUserId: e.merchant.UserId,
+			Cents:                cents,
+			Currency:             "USD",
+			FxQuoteId:            quoteId,
+		})
+		return err
+	}
+
+	pid := e.authorize(100)
+	requireCode(t, authorize(600, ""), codes.PermissionDenied)
+
+	// A decision made for an authorization that is rolled back afterwards is kept
+	e.store.addAccount(e.merchant.ID, "INCOMING", "EUR", 0)
+	e.store.addFxQuote(fxQuote{quoteId: "quote", srcCurrency: "USD", dstCurrency: "EUR", srcCents: 500, dstCents: 450, rate: "0.9", midRate: "0.92"}, time.Now().Add(time.Minute))
+	requireCode(t, authorize(300, "quote"), codes.InvalidArgument)
+
+	e.store.mu.Lock()
+	decisions := e.store.state.decisions
+	e.store.mu.Unlock()
+	want := []struct {
+		pid     string
+		outcome string
+	}{{pid, risk.Allow}, {"", risk.Deny}, {"", risk.Allow}}
+	if len(decisions) != len(want) {
+		t.Fatalf("logged %d decisions, want %d", len(decisions), len(want))
+	}
+	for i, w := range want {
+		if decisions[i].pid != w.pid || decisions[i].decision.Outcome != w.outcome {
+			t.Errorf("decision %d is %s for %q, want %s for %q", i, decisions[i].decision.Outcome, decisions[i].pid, w.outcome, w.pid)
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	tests := []struct {
+		name          string
+		caller        string
+		role          string
+		payload       func(pid string) *pb.CapturePayload
+		setup         func(e *testEnv, pid string)
+		wantCode      codes.Code
+		wantStatus    string
+		wantIncoming  int64
+		wantRevenue   int64
+		wantDefault   int64
+		wantRemaining int64
+	}{
+		{
+			name:   "full capture charges the merchant fee",
+			caller: "merchant",
+			role:   roleMerchant,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid}
+			},
+			setup: func(e *testEnv, _ string) {
+				e.store.setFeeSchedule(e.merchant.ID, "USD", feeSchedule{percentBps: 100, fixedCents: 10})
+			},
+			wantStatus:   paymentStatusCaptured,
+			wantIncoming: 287,
+			wantRevenue:  13,
+			wantDefault:  700,
+		},
+		{
+			name:   "partial capture",
+			caller: "customer",
+			role:   roleCustomer,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid, Cents: 100}
+			},
+			wantStatus:    paymentStatusPartiallyCaptured,
+			wantIncoming:  100,
+			wantDefault:   700,
+			wantRemaining: 200,
+		},
+		{
+			name:   "final capture releases the rest",
+			caller: "merchant",
+			role:   roleMerchant,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid, Cents: 100, FinalCapture: true}
+			},
+			wantStatus:   paymentStatusCaptured,
+			wantIncoming: 100,
+			wantDefault:  900,
+		},
+		{
+			name:   "more than authorized",
+			caller: "merchant",
+			role:   roleMerchant,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid, Cents: 301}
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "voided payment",
+			caller: "merchant",
+			role:   roleMerchant,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid}
+			},
+			setup: func(e *testEnv, pid string) {
+				_, err := e.impl.Void(asCaller(e.customer.UserId, roleCustomer), &pb.VoidPayload{Pid: pid})
+				requireCode(e.t, err, codes.OK)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "not a party to the payment",
+			caller: "mallory",
+			role:   roleMerchant,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid}
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			// The fee is charged after the merchant was credited, so the
+			// credit has to be rolled back when the house cannot take it
+			name:   "rolls back funds already moved",
+			caller: "merchant",
+			role:   roleMerchant,
+			payload: func(pid string) *pb.CapturePayload {
+				return &pb.CapturePayload{Pid: pid}
+			},
+			setup: func(e *testEnv, _ string) {
+				e.store.setFeeSchedule(e.merchant.ID, "USD", feeSchedule{fixedCents: 10})
+				e.store.mu.Lock()
+				for id, a := range e.store.state.accounts {
+					if a.accountType == revenueAccountType {
+						delete(e.store.state.accounts, id)
+					}
+				}
+				e.store.mu.Unlock()
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t, 1000)
+			pid := e.authorize(300)
+			if tt.setup != nil {
+				tt.setup(e, pid)
+			}
+			before := e.snapshot()
+			paymentBefore := e.payment(pid)
+
+			res, err := e.impl.Capture(asCaller(tt.caller, tt.role), tt.payload(pid))
+			requireCode(t, err, tt.wantCode)
+
+			if tt.wantCode != codes.OK {
+				for id, cents := range e.snapshot() {
+					if before[id] != cents {
+						t.Errorf("account %d changed from %d to %d", id, before[id], cents)
+					}
+				}
+				if p := e.payment(pid); p.status != paymentBefore.status || p.capturedCents != paymentBefore.capturedCents {
+					t.Errorf("payment moved from %s to %s", paymentBefore.status, p.status)
+				}
+				return
+			}
+
+			if got := e.balance(e.merchant, "INCOMING", "USD"); got != tt.wantIncoming {
+				t.Errorf("merchant INCOMING = %d, want %d", got, tt.wantIncoming)
+			}
+			if got := e.balance(e.customer, "DEFAULT", "USD"); got != tt.wantDefault {
+				t.Errorf("customer DEFAULT = %d, want %d", got, tt.wantDefault)
+			}
+			if got := e.balance(e.customer, "PAYMENT", "USD"); got != tt.wantRemaining {
+				t.Errorf("customer PAYMENT = %d, want %d", got, tt.wantRemaining)
+			}
+			if got := e.balance(e.house, revenueAccountType, "USD"); got != tt.wantRevenue {
+				t.Errorf("house REVENUE = %d, want %d", got, tt.wantRevenue)
+			}
+			if res.RemainingCents != tt.wantRemaining {
+				t.Errorf("remaining = %d, want %d", res.RemainingCents, tt.wantRemaining)
+			}
+			if p := e.payment(pid); p.status != tt.wantStatus {
+				t.Errorf("payment is %s, want %s", p.status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCaptureIdempotentReplay(t *testing.T) {
+	e := newTestEnv(t, 1000)
+	pid := e.authorize(300)
+	ctx := withIdempotencyKey(asCaller(e.merchant.UserId, roleMerchant), "key")
+	payload := &pb.CapturePayload{Pid: pid, Cents: 100}
+
+	first, err := e.impl.Capture(ctx, payload)
+	requireCode(t, err, codes.OK)
+	second, err := e.impl.Capture(ctx, payload)
+	requireCode(t, err, codes.OK)
+
+	if first.CaptureId != second.CaptureId {
+		t.Errorf("replay returned capture %s, want %s", second.CaptureId, first.CaptureId)
+	}
+	if got := e.balance(e.merchant, "INCOMING", "USD"); got != 100 {
+		t.Errorf("merchant INCOMING = %d after replay, want 100", got)
+	}
+	if p := e.payment(pid); p.capturedCents != 100 {
+		t.Errorf("captured %d after replay, want 100", p.capturedCents)
+	}
+
+	// Keys are scoped to the caller: the customer picking the same key gets a
+	// capture of its own instead of the merchant's response or a conflict
+	third, err := e.impl.Capture(withIdempotencyKey(asCaller(e.customer.UserId, roleCustomer), "key"), &pb.CapturePayload{Pid: pid, Cents: 50})
+	requireCode(t, err, codes.OK)
+	if third.CaptureId == first.CaptureId {
+		t.Errorf("another caller's key replayed capture %s", first.CaptureId)
+	}
+	if p := e.payment(pid); p.capturedCents != 150 {
+		t.Errorf("captured %d, want 150", p.capturedCents)
+	}
+}
+
+func TestSplitPaymentLegMerchantCannotActOnWholePayment(t *testing.T) {
+	e := newTestEnv(t, 1000)
+	other := e.addMerchant("other")
+	pid := e.authorizeSplit(other)
+
+	for _, merchant := range []wallet{e.merchant, other} {
+		ctx := asCaller(merchant.UserId, roleMerchant)
+		_, err := e.impl.Void(ctx, &pb.VoidPayload{Pid: pid})
+		requireCode(t, err, codes.PermissionDenied)
+		_, err = e.impl.Capture(ctx, &pb.CapturePayload{Pid: pid})
+		requireCode(t, err, codes.PermissionDenied)
+
+		// Every merchant may still read the payment
+		requireCode(t, callerFromContext(ctx).requirePaymentParty(e.payment(pid)), codes.OK)
+	}
+	if p := e.payment(pid); p.status != paymentStatusAuthorized {
+		t.Fatalf("payment is %s, want %s", p.status, paymentStatusAuthorized)
+	}
+
+	_, err := e.impl.Void(asCaller(e.customer.UserId, roleCustomer), &pb.VoidPayload{Pid: pid})
+	requireCode(t, err, codes.OK)
+	if got := e.balance(e.customer, "DEFAULT", "USD"); got != 1000 {
+		t.Errorf("customer DEFAULT = %d, want 1000", got)
+	}
+}
+
+func TestRefundSplitPayment(t *testing.T) {
+	e := newTestEnv(t, 1000)
+	other := e.addMerchant("other")
+	pid := e.authorizeSplit(other)
+	_, err := e.impl.Capture(asCaller("admin", roleAdmin), &pb.CapturePayload{Pid: pid})
+	requireCode(t, err, codes.OK)
+
+	// Each merchant refunds its own leg, within what the leg was captured for
+	_, err = e.impl.Refund(asCaller(other.UserId, roleMerchant), &pb.RefundPayload{Pid: pid, Cents: 101})
+	requireCode(t, err, codes.FailedPrecondition)
+	_, err = e.impl.Refund(asCaller(other.UserId, roleMerchant), &pb.RefundPayload{Pid: pid, Cents: 100})
+	requireCode(t, err, codes.OK)
+	_, err = e.impl.Refund(asCaller(other.UserId, roleMerchant), &pb.RefundPayload{Pid: pid, Cents: 1, MerchantWalletUserId: e.merchant.UserId})
+	requireCode(t, err, codes.PermissionDenied)
+	_, err = e.impl.Refund(asCaller("admin", roleAdmin), &pb.RefundPayload{Pid: pid, Cents: 1})
+	requireCode(t, err, codes.InvalidArgument)
+
+	if got := e.balance(other, "INCOMING", "USD"); got != 0 {
+		t.Errorf("other INCOMING = %d, want 0", got)
+	}
+	if got := e.balance(e.merchant, "INCOMING", "USD"); got != 200 {
+		t.Errorf("merchant INCOMING = %d, want 200", got)
+	}
+	if p := e.payment(pid); p.status != paymentStatusPartiallyRefunded || p.legs[1].refundedCents != 100 || p.legs[0].refundedCents != 0 {
+		t.Errorf("payment is %s with legs %+v, want PARTIALLY_REFUNDED with the second leg refunded", p.status, p.legs)
+	}
+
+	_, err = e.impl.Refund(asCaller("admin", roleAdmin), &pb.RefundPayload{Pid: pid, Cents: 200, MerchantWalletUserId: e.merchant.UserId})
+	requireCode(t, err, codes.OK)
+	if got := e.balance(e.customer, "DEFAULT", "USD"); got != 1000 {
+		t.Errorf("customer DEFAULT = %d, want 1000", got)
+	}
+	if p := e.payment(pid); p.status != paymentStatusRefunded {
+		t.Errorf("payment is %s, want %s", p.status, paymentStatusRefunded)
+	}
+}
+
+// settle moves the whole INCOMING balance of w to its SETTLED account, as a
+// settlement run would.
+func (e *testEnv) settle(w wallet) {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	var incoming, settled account
+	for _, a := range e.store.state.accounts {
+		if a.walletID == w.ID && a.currency == "USD" {
+			switch a.accountType {
+			case "INCOMING":
+				incoming = a
+			case "SETTLED":
+				settled = a
+			}
+		}
+	}
+	settled.cents += incoming.cents
+	incoming.cents = 0
+	e.store.state.accounts[incoming.ID] = incoming
+	e.store.state.accounts[settled.ID] = settled
+}
+
+func TestRefundReturnsFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		settled bool
+		refunds []int64
+		// after each refund
+		wantIncoming []int64
+		wantRevenue  []int64
+	}{
+		{
+			// 1% + 10 on 300 is a fee of 13: the first refund returns
+			// 13*100/300 = 4 of it and the second the remaining 9
+			name:         "partial refunds return the fee's share",
+			refunds:      []int64{100, 200},
+			wantIncoming: []int64{191, 0},
+			wantRevenue:  []int64{9, 0},
+		},
+		{
+			name:         "settled payment is refunded from SETTLED",
+			settled:      true,
+			refunds:      []int64{300},
+			wantIncoming: []int64{0},
+			wantRevenue:  []int64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t, 1000)
+			e.store.setFeeSchedule(e.merchant.ID, "USD", feeSchedule{percentBps: 100, fixedCents: 10})
+			pid := e.authorize(300)
+			merchant := asCaller(e.merchant.UserId, roleMerchant)
+			_, err := e.impl.Capture(merchant, &pb.CapturePayload{Pid: pid})
+			requireCode(t, err, codes.OK)
+			if tt.settled {
+				e.settle(e.merchant)
+			}
+
+			for i, cents := range tt.refunds {
+				_, err = e.impl.Refund(merchant, &pb.RefundPayload{Pid: pid, Cents: cents})
+				requireCode(t, err, codes.OK)
+				if got := e.balance(e.merchant, "INCOMING", "USD"); got != tt.wantIncoming[i] {
+					t.Errorf("after refund %d merchant INCOMING = %d, want %d", i, got, tt.wantIncoming[i])
+				}
+				if got := e.balance(e.house, revenueAccountType, "USD"); got != tt.wantRevenue[i] {
+					t.Errorf("after refund %d house REVENUE = %d, want %d", i, got, tt.wantRevenue[i])
+				}
+			}
+
+			if got := e.balance(e.merchant, "SETTLED", "USD"); got != 0 {
+				t.Errorf("merchant SETTLED = %d, want 0", got)
+			}
+			if got := e.balance(e.customer, "DEFAULT", "USD"); got != 1000 {
+				t.Errorf("customer DEFAULT = %d, want 1000", got)
+			}
+			if p := e.payment(pid); p.status != paymentStatusRefunded {
+				t.Errorf("payment is %s, want %s", p.status, paymentStatusRefunded)
+			}
+		})
+	}
+}
+
+func TestRefundAndVoidPartiallyCapturedPayment(t *testing.T) {
+	tests := []struct {
+		name string
+		// after a capture of 100 out of 300 and a refund of refund
+		refund     int64
+		finish     func(e *testEnv, pid string) error
+		wantStatus string
+		wantDefault,
+		wantIncoming,
+		wantRevenue int64
+	}{
+		{
+			name:   "void releases the rest",
+			refund: 40,
+			finish: func(e *testEnv, pid string) error {
+				_, err := e.impl.Void(asCaller(e.customer.UserId, roleCustomer), &pb.VoidPayload{Pid: pid})
+				return err
+			},
+			wantStatus:   paymentStatusPartiallyRefunded,
+			wantDefault:  940,
+			wantIncoming: 60 - 11 + 4,
+			wantRevenue:  11 - 4,
+		},
+		{
+			name:   "void after refunding everything captured",
+			refund: 100,
+			finish: func(e *testEnv, pid string) error {
+				_, err := e.impl.Void(asCaller(e.merchant.UserId, roleMerchant), &pb.VoidPayload{Pid: pid})
+				return err
+			},
+			wantStatus:  paymentStatusRefunded,
+			wantDefault: 1000,
+		},
+		{
+			// The second capture's fee changes the fee rate, so the final
+			// refund returns what is left of the fees rather than its share
+			name:   "capture again and refund the rest",
+			refund: 40,
+			finish: func(e *testEnv, pid string) error {
+				merchant := asCaller(e.merchant.UserId, roleMerchant)
+				_, err := e.impl.Capture(merchant, &pb.CapturePayload{Pid: pid})
+				if err != nil {
+					return err
+				}
+				_, err = e.impl.Refund(merchant, &pb.RefundPayload{Pid: pid, Cents: 260})
+				return err
+			},
+			wantStatus:  paymentStatusRefunded,
+			wantDefault: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t, 1000)
+			e.store.setFeeSchedule(e.merchant.ID, "USD", feeSchedule{percentBps: 100, fixedCents: 10})
+			pid := e.authorize(300)
+			merchant := asCaller(e.merchant.UserId, roleMerchant)
+			_, err := e.impl.Capture(merchant, &pb.CapturePayload{Pid: pid, Cents: 100})
+			requireCode(t, err, codes.OK)
+
+			// Refunding what was captured leaves the rest of the hold open
+			_, err = e.impl.Refund(merchant, &pb.RefundPayload{Pid: pid, Cents: 101})
+			requireCode(t, err, codes.FailedPrecondition)
+			_, err = e.impl.Refund(merchant, &pb.RefundPayload{Pid: pid, Cents: tt.refund})
+			requireCode(t, err, codes.OK)
+			if p := e.payment(pid); p.status != paymentStatusPartiallyCaptured || p.remaining() != 200 {
+				t.Fatalf("payment is %s with %d remaining after refund, want %s with 200", p.status, p.remaining(), paymentStatusPartiallyCaptured)
+			}
+
+			requireCode(t, tt.finish(e, pid), codes.OK)
+
+			if p := e.payment(pid); p.status != tt.wantStatus || p.remaining() != 0 {
+				t.Errorf("payment is %s with %d remaining, want %s with 0", p.status, p.remaining(), tt.wantStatus)
+			}
+			if got := e.balance(e.customer, "DEFAULT", "USD"); got != tt.wantDefault {
+				t.Errorf("customer DEFAULT = %d, want %d", got, tt.wantDefault)
+			}
+			if got := e.balance(e.customer, "PAYMENT", "USD"); got != 0 {
+				t.Errorf("customer PAYMENT = %d, want 0", got)
+			}
+			if got := e.balance(e.merchant, "INCOMING", "USD"); got != tt.wantIncoming {
+				t.Errorf("merchant INCOMING = %d, want %d", got, tt.wantIncoming)
+			}
+			if got := e.balance(e.house, revenueAccountType, "USD"); got != tt.wantRevenue {
+				t.Errorf("house REVENUE = %d, want %d", got, tt.wantRevenue)
+			}
+		})
+	}
+}
+
+func TestVelocityIgnoresReleasedAmounts(t *testing.T) {
+	e := newTestEnv(t, 2000)
+	e.store.setVelocityLimit(walletTypeCustomer, 0, "USD", velocityLimit{maxDailyCents: 1000})
+	merchant := asCaller(e.merchant.UserId, roleMerchant)
+
+	// A voided authorization gives its amount back to the daily limit
+	pid := e.authorize(800)
+	_, err := e.impl.Void(merchant, &pb.VoidPayload{Pid: pid})
+	requireCode(t, err, codes.OK)
+
+	// So does the part of an authorization a final capture released
+	pid = e.authorize(800)
+	_, err = e.impl.Capture(merchant, &pb.CapturePayload{Pid: pid, Cents: 300, FinalCapture: true})
+	requireCode(t, err, codes.OK)
+
+	pid = e.authorize(700)
+	_, err = e.impl.Authorize(asCaller(e.customer.UserId, roleCustomer), &pb.AuthorizePayload{
+		CustomerWalletUserId: e.customer.UserId,
+		MerchantWalletUserId: e.merchant.UserId,
+		Cents:                1,
+		Currency:             "USD",
+	})
+	requireCode(t, err, codes.ResourceExhausted)
+	if p := e.payment(pid); p.authorizedCents != 700 {
+		t.Errorf("authorized %d, want 700", p.authorizedCents)
+	}
+}
+
+func TestIncrementAuthorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		setup    func(e *testEnv) string
+		wantCode codes.Code
+		// after a successful increment of 200
+		wantAuthorized int64
+		wantRemaining  int64
+	}{
+		{
+			name:           "raises the hold",
+			caller:         "customer",
+			setup:          func(e *testEnv) string { return e.authorize(300) },
+			wantAuthorized: 500,
+			wantRemaining:  500,
+		},
+		{
+			name:   "raises what a partial capture left",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				pid := e.authorize(300)
+				_, err := e.impl.Capture(asCaller(e.merchant.UserId, roleMerchant), &pb.CapturePayload{Pid: pid, Cents: 100})
+				requireCode(e.t, err, codes.OK)
+				return pid
+			},
+			wantAuthorized: 500,
+			wantRemaining:  400,
+		},
+		{
+			name:     "only the customer can spend more",
+			caller:   "merchant",
+			setup:    func(e *testEnv) string { return e.authorize(300) },
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "expired authorization",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				pid := e.authorize(300)
+				e.impl.authorizationTTL = time.Hour
+				e.store.mu.Lock()
+				p := e.store.state.payments[pid]
+				p.createdAt = time.Now().Add(-2 * time.Hour).Unix()
+				e.store.state.payments[pid] = p
+				e.store.mu.Unlock()
+				return pid
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "voided payment",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				pid := e.authorize(300)
+				_, err := e.impl.Void(asCaller(e.customer.UserId, roleCustomer), &pb.VoidPayload{Pid: pid})
+				requireCode(e.t, err, codes.OK)
+				return pid
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "captured payment",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				pid := e.authorize(300)
+				_, err := e.impl.Capture(asCaller(e.merchant.UserId, roleMerchant), &pb.CapturePayload{Pid: pid})
+				requireCode(e.t, err, codes.OK)
+				return pid
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "fx quoted payment",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				pid := e.authorize(300)
+				e.store.mu.Lock()
+				for i, mt := range e.store.state.transactions {
+					if mt.pid == pid && mt.transactionType == transactionTypeAuthorize {
+						e.store.state.transactions[i].fxQuoteId = "quote"
+					}
+				}
+				e.store.mu.Unlock()
+				return pid
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "split payment",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				return e.authorizeSplit(e.addMerchant("other"))
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "velocity limit",
+			caller: "customer",
+			setup: func(e *testEnv) string {
+				e.store.setVelocityLimit(walletTypeCustomer, 0, "USD", velocityLimit{maxSingleCents: 400})
+				return e.authorize(300)
+			},
+			wantCode: codes.ResourceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t, 1000)
+			pid := tt.setup(e)
+			before := e.snapshot()
+			paymentBefore := e.payment(pid)
+
+			res, err := e.impl.IncrementAuthorization(asCaller(tt.caller, roleCustomer), &pb.IncrementAuthorizationPayload{Pid: pid, AdditionalCents: 200})
+			requireCode(t, err, tt.wantCode)
+
+			if tt.wantCode != codes.OK {
+				for id, cents := range e.snapshot() {
+					if before[id] != cents {
+						t.Errorf("account %d changed from %d to %d", id, before[id], cents)
+					}
+				}
+				if p := e.payment(pid); p.authorizedCents != paymentBefore.authorizedCents {
+					t.Errorf("authorized %d, want %d", p.authorizedCents, paymentBefore.authorizedCents)
+				}
+				return
+			}
+
+			if got := e.balance(e.customer, "DEFAULT", "USD"); got != 500 {
+				t.Errorf("DEFAULT = %d, want 500", got)
+			}
+			if got := e.balance(e.customer, "PAYMENT", "USD"); got != tt.wantRemaining {
+				t.Errorf("PAYMENT = %d, want %d", got, tt.wantRemaining)
+			}
+			if res.AuthorizedCents != tt.wantAuthorized || res.RemainingCents != tt.wantRemaining {
+				t.Errorf("response authorized %d with %d remaining, want %d with %d", res.AuthorizedCents, res.RemainingCents, tt.wantAuthorized, tt.wantRemaining)
+			}
+			if p := e.payment(pid); p.authorizedCents != tt.wantAuthorized {
+				t.Errorf("payment authorized %d, want %d", p.authorizedCents, tt.wantAuthorized)
+			}
+		})
+	}
+}
+
+func TestIncrementAuthorizationIdempotentReplay(t *testing.T) {
+	e := newTestEnv(t, 1000)
+	pid := e.authorize(300)
+	ctx := withIdempotencyKey(asCaller(e.customer.UserId, roleCustomer), "key")
+	payload := &pb.IncrementAuthorizationPayload{Pid: pid, AdditionalCents: 200}
+
+	first, err := e.impl.IncrementAuthorization(ctx, payload)
+	requireCode(t, err, codes.OK)
+	second, err := e.impl.IncrementAuthorization(ctx, payload)
+	requireCode(t, err, codes.OK)
+
+	if second.AuthorizedCents != first.AuthorizedCents {
+		t.Errorf("replay authorized %d, want %d", second.AuthorizedCents, first.AuthorizedCents)
+	}
+	if p := e.payment(pid); p.authorizedCents != 500 {
+		t.Errorf("authorized %d after replay, want 500", p.authorizedCents)
+	}
+	if got := e.balance(e.customer, "PAYMENT", "USD"); got != 500 {
+		t.Errorf("PAYMENT = %d after replay, want 500", got)
+	}
+
+	payload.AdditionalCents = 100
+	_, err = e.impl.IncrementAuthorization(ctx, payload)
+	requireCode(t, err, codes.InvalidArgument)
+}
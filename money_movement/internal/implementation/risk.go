@@ -0,0 +1,97 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/MikePham0630/gomicro/internal/risk"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reasonRiskDenied is reported in the ErrorInfo of an authorization the risk
+// rules denied
+const reasonRiskDenied = "RISK_DENIED"
+
+const insertRiskDecisionQuery = "INSERT INTO risk_decisions (pid, customer_user_id, merchant_user_id, cents, currency, decision, reasons) VALUES (NULLIF(?, ''), ?, ?, ?, ?, ?, ?)"
+
+// riskDecision is the log entry of one scored authorization. Authorizations
+// that were denied or did not commit have no pid.
+type riskDecision struct {
+	pid            string
+	customerUserId string
+	merchantUserId string
+	cents          int64
+	currency       string
+	decision       risk.Decision
+}
+
+func riskDeniedError(d risk.Decision) error {
+	reasons := strings.Join(d.Reasons, ",")
+	st := status.Newf(codes.PermissionDenied, "%s: authorization denied by risk rules: %s", reasonRiskDenied, reasons)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reasonRiskDenied,
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			"reasons": reasons,
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// logRiskDecision records d in a unit of work of its own, so that a decision is
+// kept even when the authorization it was made for is denied or rolled back.
+func (impl *Implementation) logRiskDecision(ctx context.Context, d riskDecision) error {
+	tx, err := impl.store.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.insertRiskDecision(d)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func insertRiskDecision(tx *sql.Tx, d riskDecision) error {
+	stmt, err := tx.Prepare(insertRiskDecisionQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	_, err = stmt.Exec(d.pid, d.customerUserId, d.merchantUserId, d.cents, d.currency, d.decision.Outcome, joinRiskReasons(d.decision.Reasons))
+	if err != nil {
+		return dbError("failed to log risk decision", err)
+	}
+
+	return nil
+}
+
+// Risk reasons are stored comma separated; the rules file names them, e.g.
+// LARGE_AMOUNT.
+func joinRiskReasons(reasons []string) string {
+	return strings.Join(reasons, ",")
+}
+
+func splitRiskReasons(reasons string) []string {
+	if reasons == "" {
+		return nil
+	}
+	return strings.Split(reasons, ",")
+}
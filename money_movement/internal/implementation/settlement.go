@@ -0,0 +1,383 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/producer"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	transactionTypeSettlement         = "SETTLEMENT"
+	transactionTypeSettlementReversal = "SETTLEMENT_REVERSAL"
+)
+
+const (
+	selectIncomingAccountsQuery = "SELECT id FROM accounts WHERE account_type = 'INCOMING'"
+	lockAccountQuery            = "SELECT id, cents, account_type, currency, wallet_id FROM accounts WHERE id = ? FOR UPDATE"
+
+	// selectUnsettledQuery returns every movement on an INCOMING account that
+	// no settlement has included yet, as the signed change of its balance.
+	// Credits are net of the fee charged on them and refunds of the fee
+	// returned with them.
+	selectUnsettledQuery = `SELECT t.id, t.pid, t.transaction_type, CASE WHEN t.dst_account_id = ? THEN t.converted_amount - t.fee_amount ELSE t.fee_amount - t.amount END
+	FROM transactions t LEFT JOIN settlement_items si ON si.transaction_id = t.id
+	WHERE (t.dst_account_id = ? OR t.src_account_id = ?) AND t.transaction_type <> 'SETTLEMENT' AND si.transaction_id IS NULL AND t.created_at < ?
+	ORDER BY t.id`
+
+	insertSettlementQuery     = "INSERT INTO settlements (settlement_id, merchant_user_id, merchant_wallet_id, currency, cents, cutoff) VALUES (?, ?, ?, ?, ?, ?)"
+	insertSettlementItemQuery = "INSERT INTO settlement_items (settlement_id, transaction_id, pid, transaction_type, cents) VALUES (?, ?, ?, ?, ?)"
+	listSettlementsQuery      = "SELECT settlement_id, merchant_user_id, currency, cents, UNIX_TIMESTAMP(cutoff), UNIX_TIMESTAMP(created_at) FROM settlements"
+	listSettlementItemsQuery  = "SELECT transaction_id, pid, transaction_type, cents FROM settlement_items WHERE settlement_id = ? ORDER BY transaction_id"
+)
+
+// RunSettlementScheduler settles every merchant's INCOMING balances every
+// interval until ctx is done.
+func (impl *Implementation) RunSettlementScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			settled, err := impl.SettleMerchants(time.Now())
+			if err != nil {
+				log.Printf("Failed to settle merchants: %v", err)
+				continue
+			}
+			if settled > 0 {
+				log.Printf("Created %d settlements", settled)
+			}
+		}
+	}
+}
+
+// SettleMerchants moves what every INCOMING account received before cutoff
+// into the merchant's SETTLED account of the same currency and records a
+// settlement listing the included payments.
+func (impl *Implementation) SettleMerchants(cutoff time.Time) (int, error) {
+	rows, err := impl.db.Query(selectIncomingAccountsQuery)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to query incoming accounts: %v", err)
+	}
+
+	var accountIds []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, status.Errorf(codes.Internal, "failed to scan incoming account: %v", err)
+		}
+		accountIds = append(accountIds, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to query incoming accounts: %v", err)
+	}
+
+	settled := 0
+	for _, accountId := range accountIds {
+		created, err := retryOnConflict(context.Background(), func() (bool, error) {
+			return impl.settleAccount(accountId, cutoff)
+		})
+		if err != nil {
+			log.Printf("Failed to settle account %d: %v", accountId, err)
+			continue
+		}
+		if created {
+			settled++
+		}
+	}
+
+	return settled, nil
+}
+
+type settlementItem struct {
+	transactionId   int32
+	pid             string
+	transactionType string
+	cents           int64
+}
+
+// settleAccount returns false when there was nothing to settle. Refunds can
+// leave the net amount at or below zero; those movements are carried over to
+// the next run.
+func (impl *Implementation) settleAccount(accountId int32, cutoff time.Time) (bool, error) {
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	// Captures and refunds update the INCOMING row, so holding its lock keeps
+	// the set of unsettled movements stable until commit
+	incomingAccount, err := lockAccount(tx, accountId)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	items, err := fetchUnsettled(tx, incomingAccount.ID, cutoff)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	var amount int64
+	for _, item := range items {
+		amount += item.cents
+	}
+	if amount <= 0 {
+		return false, tx.Rollback()
+	}
+
+	merchantWallet, err := fetchWalletWithWalletId(tx, incomingAccount.walletID)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	settledAccount, err := fetchAccount(tx, merchantWallet.ID, "SETTLED", incomingAccount.currency)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	err = transfer(tx, incomingAccount, settledAccount, amount)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	settlementId := uuid.NewString()
+	err = createTransaction(tx, settlementId, transactionTypeSettlement, incomingAccount, settledAccount, merchantWallet, merchantWallet, merchantWallet, amount)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	err = insertSettlement(tx, settlementId, merchantWallet, incomingAccount.currency, amount, cutoff, items)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, err
+	}
+
+	pids := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if !seen[item.pid] {
+			seen[item.pid] = true
+			pids = append(pids, item.pid)
+		}
+	}
+
+	err = producer.EnqueueSettlementMessage(tx, settlementId, merchantWallet.UserId, amount, incomingAccount.currency, pids, cutoff)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return false, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return false, status.Errorf(codes.Internal, "failed to enqueue settlement message: %v", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return true, nil
+}
+
+func (impl *Implementation) ListSettlements(ctx context.Context, listSettlementsPayload *pb.ListSettlementsPayload) (*pb.ListSettlementsResponse, error) {
+	if err := callerFromContext(ctx).requireMerchant(listSettlementsPayload.GetMerchantUserId()); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"merchant_user_id = ?"}
+	args := []any{listSettlementsPayload.GetMerchantUserId()}
+	if createdFrom := listSettlementsPayload.GetCreatedFrom(); createdFrom != 0 {
+		conditions = append(conditions, "created_at >= FROM_UNIXTIME(?)")
+		args = append(args, createdFrom)
+	}
+	if createdTo := listSettlementsPayload.GetCreatedTo(); createdTo != 0 {
+		conditions = append(conditions, "created_at < FROM_UNIXTIME(?)")
+		args = append(args, createdTo)
+	}
+	query := listSettlementsQuery + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY created_at DESC, settlement_id"
+
+	rows, err := impl.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query settlements: %v", err)
+	}
+
+	response := &pb.ListSettlementsResponse{}
+	for rows.Next() {
+		var s pb.Settlement
+		err := rows.Scan(&s.SettlementId, &s.MerchantUserId, &s.Currency, &s.Cents, &s.Cutoff, &s.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "failed to scan settlement: %v", err)
+		}
+		response.Settlements = append(response.Settlements, &s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query settlements: %v", err)
+	}
+
+	for _, s := range response.Settlements {
+		s.Items, err = impl.listSettlementItems(ctx, s.SettlementId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+func (impl *Implementation) listSettlementItems(ctx context.Context, settlementId string) ([]*pb.SettlementItem, error) {
+	rows, err := impl.db.QueryContext(ctx, listSettlementItemsQuery, settlementId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query settlement items: %v", err)
+	}
+	defer rows.Close()
+
+	var items []*pb.SettlementItem
+	for rows.Next() {
+		var item pb.SettlementItem
+		err := rows.Scan(&item.TransactionId, &item.Pid, &item.TransactionType, &item.Cents)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan settlement item: %v", err)
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query settlement items: %v", err)
+	}
+
+	return items, nil
+}
+
+func lockAccount(tx *sql.Tx, accountId int32) (account, error) {
+	var a account
+	stmt, err := tx.Prepare(lockAccountQuery)
+	if err != nil {
+		return a, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = stmt.QueryRow(accountId).Scan(&a.ID, &a.cents, &a.accountType, &a.currency, &a.walletID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return a, status.Errorf(codes.NotFound, "account not found: %d", accountId)
+		}
+		return a, dbError("failed to lock account", err)
+	}
+	return a, nil
+}
+
+func fetchUnsettled(tx *sql.Tx, accountId int32, cutoff time.Time) ([]settlementItem, error) {
+	stmt, err := tx.Prepare(selectUnsettledQuery)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	rows, err := stmt.Query(accountId, accountId, accountId, cutoff)
+	if err != nil {
+		return nil, dbError("failed to query unsettled transactions", err)
+	}
+	defer rows.Close()
+
+	var items []settlementItem
+	for rows.Next() {
+		var item settlementItem
+		err := rows.Scan(&item.transactionId, &item.pid, &item.transactionType, &item.cents)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan unsettled transaction: %v", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to query unsettled transactions", err)
+	}
+
+	return items, nil
+}
+
+func insertSettlement(tx *sql.Tx, settlementId string, merchantWallet wallet, currencyCode string, amount int64, cutoff time.Time, items []settlementItem) error {
+	stmt, err := tx.Prepare(insertSettlementQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert settlement statement: %v", err)
+	}
+
+	_, err = stmt.Exec(settlementId, merchantWallet.UserId, merchantWallet.ID, currencyCode, amount, cutoff)
+	if err != nil {
+		return dbError("failed to insert settlement", err)
+	}
+
+	itemStmt, err := tx.Prepare(insertSettlementItemQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert settlement item statement: %v", err)
+	}
+
+	for _, item := range items {
+		_, err = itemStmt.Exec(settlementId, item.transactionId, item.pid, item.transactionType, item.cents)
+		if err != nil {
+			return dbError("failed to insert settlement item", err)
+		}
+	}
+
+	return nil
+}
+
+// reverseSettlement takes back from the merchant's SETTLED account whatever
+// its INCOMING account lacks to pay out amount, so that payments can still be
+// refunded after they were settled.
+func reverseSettlement(tx unitOfWork, pid string, merchantWallet wallet, incomingAccount account, amount int64) error {
+	shortfall := amount - incomingAccount.cents
+	if shortfall <= 0 {
+		return nil
+	}
+
+	settledAccount, err := tx.fetchAccount(merchantWallet.ID, "SETTLED", incomingAccount.currency)
+	if err != nil {
+		return err
+	}
+
+	err = tx.transfer(settledAccount, incomingAccount, shortfall)
+	if err != nil {
+		return err
+	}
+
+	return tx.insertTransaction(newTransaction(pid, transactionTypeSettlementReversal, settledAccount, incomingAccount, merchantWallet, merchantWallet, merchantWallet, shortfall))
+}
@@ -0,0 +1,142 @@
+package mm
+
+import (
+	"github.com/MikePham0630/gomicro/internal/producer"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resolvePaymentLegs checks the legs of a split authorization: every merchant
+// appears once, has an active wallet that can receive currencyCode and gets a
+// positive amount, and the amounts add up to total. Payments to a single
+// merchant have no legs.
+func resolvePaymentLegs(tx unitOfWork, legsPayload []*pb.PaymentLeg, total int64, currencyCode string) ([]paymentLeg, error) {
+	if len(legsPayload) == 0 {
+		return nil, nil
+	}
+
+	legs := make([]paymentLeg, 0, len(legsPayload))
+	seen := make(map[string]bool, len(legsPayload))
+	var sum int64
+	for _, l := range legsPayload {
+		if l.GetCents() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "leg for merchant %s must have a positive amount", l.GetMerchantWalletUserId())
+		}
+		if seen[l.GetMerchantWalletUserId()] {
+			return nil, status.Errorf(codes.InvalidArgument, "merchant %s appears in more than one leg", l.GetMerchantWalletUserId())
+		}
+		seen[l.GetMerchantWalletUserId()] = true
+
+		w, err := tx.fetchWallet(l.GetMerchantWalletUserId())
+		if err != nil {
+			return nil, err
+		}
+		if w.status != walletStatusActive {
+			return nil, status.Errorf(codes.FailedPrecondition, "wallet is closed")
+		}
+
+		_, err = tx.fetchAccount(w.ID, "INCOMING", currencyCode)
+		if err != nil {
+			return nil, err
+		}
+
+		legs = append(legs, paymentLeg{merchantUserId: w.UserId, merchantWalletId: w.ID, cents: l.GetCents()})
+		sum += l.GetCents()
+	}
+
+	if sum != total {
+		return nil, status.Errorf(codes.InvalidArgument, "legs add up to %d but the payment is for %d", sum, total)
+	}
+
+	return legs, nil
+}
+
+// refundLeg returns the index of the leg of split payment p that a refund is
+// drawn from. Each merchant refunds its own leg; admins have to name it.
+func refundLeg(c caller, p payment, merchantUserId string) (int, error) {
+	if merchantUserId == "" {
+		if c.isAdmin() {
+			return 0, status.Errorf(codes.InvalidArgument, "a refund of split payment %s must name the merchant whose leg is refunded", p.pid)
+		}
+		merchantUserId = c.userId
+	}
+
+	err := c.requireMerchant(merchantUserId)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, leg := range p.legs {
+		if leg.merchantUserId == merchantUserId {
+			return i, nil
+		}
+	}
+	return 0, status.Errorf(codes.InvalidArgument, "merchant %s has no leg in payment %s", merchantUserId, p.pid)
+}
+
+// captureLeg credits one merchant with amount out of the customer's PAYMENT
+// account, charges the merchant's fee and records the CAPTURE transaction.
+func captureLeg(tx unitOfWork, authorizeTransaction transaction, srcAccount account, customerWallet wallet, merchantWalletId int32, captureId string, amount int64) (producer.CaptureLeg, error) {
+	var leg producer.CaptureLeg
+
+	merchantWallet, err := tx.fetchWalletWithWalletId(merchantWalletId)
+	if err != nil {
+		return leg, err
+	}
+
+	dstMerchantAccount, err := tx.fetchAccount(merchantWallet.ID, "INCOMING", authorizeTransaction.convertedCurrency)
+	if err != nil {
+		return leg, err
+	}
+
+	convertedAmount, spread, err := convertCapture(authorizeTransaction, amount)
+	if err != nil {
+		return leg, err
+	}
+
+	if authorizeTransaction.fxQuoteId == "" {
+		err = tx.transfer(srcAccount, dstMerchantAccount, amount)
+	} else {
+		err = fxTransfer(tx, srcAccount, dstMerchantAccount, amount, convertedAmount, spread)
+	}
+	if err != nil {
+		return leg, err
+	}
+
+	// The merchant's fee comes out of what it was credited and goes to the house
+	schedule, err := tx.fetchFeeSchedule(merchantWallet.ID, dstMerchantAccount.currency)
+	if err != nil {
+		return leg, err
+	}
+
+	fee := schedule.fee(convertedAmount)
+	err = chargeFee(tx, dstMerchantAccount, fee)
+	if err != nil {
+		return leg, err
+	}
+
+	captureTransaction := newTransaction(authorizeTransaction.pid, transactionTypeCapture, srcAccount, dstMerchantAccount, customerWallet, customerWallet, merchantWallet, amount)
+	captureTransaction.captureId = captureId
+	captureTransaction.convertedAmount = convertedAmount
+	captureTransaction.fxQuoteId = authorizeTransaction.fxQuoteId
+	captureTransaction.fxRate = authorizeTransaction.fxRate
+	captureTransaction.fxMidRate = authorizeTransaction.fxMidRate
+	captureTransaction.fxSpreadAmount = spread
+	captureTransaction.feeAmount = fee
+	err = tx.insertTransaction(captureTransaction)
+	if err != nil {
+		return leg, err
+	}
+
+	return producer.CaptureLeg{
+		MerchantUserId: merchantWallet.UserId,
+		Amount:         amount,
+		Fee: producer.CaptureFee{
+			Gross:    convertedAmount,
+			Fee:      fee,
+			Net:      convertedAmount - fee,
+			Currency: dstMerchantAccount.currency,
+		},
+	}, nil
+}
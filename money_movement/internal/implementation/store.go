@@ -0,0 +1,151 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/MikePham0630/gomicro/internal/producer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// store is the storage Authorize, Capture, Void and Refund run against. The
+// MySQL store is used in production; the in-memory store lets the payment flow
+// run without a database.
+type store interface {
+	begin(ctx context.Context) (unitOfWork, error)
+}
+
+// unitOfWork is one atomic set of reads and writes. Nothing it writes is
+// visible to other units of work before Commit, and Rollback discards all of
+// it. Rows read for update stay locked until the unit of work ends.
+type unitOfWork interface {
+	Commit() error
+	Rollback() error
+
+	lookupIdempotentResponse(userId, key, method string, req, resp proto.Message) (string, bool, error)
+	saveIdempotentResponse(userId, key, method, fingerprint string, resp proto.Message) error
+
+	fetchWallet(userId string) (wallet, error)
+	fetchWalletWithWalletId(walletId int32) (wallet, error)
+	fetchAccount(walletId int32, accountType, currencyCode string) (account, error)
+	transfer(srcAccount, dstAccount account, amount int64) error
+
+	insertTransaction(t transaction) error
+	fetchTransaction(pid string) (transaction, error)
+
+	insertPayment(p payment) error
+	fetchPayment(pid string) (payment, error)
+	updatePayment(p payment) error
+
+	fetchFxQuote(quoteId string) (fxQuote, error)
+	useFxQuote(quote fxQuote, pid, currencyCode string, amount int64) error
+	fetchFeeSchedule(merchantWalletId int32, currencyCode string) (feeSchedule, error)
+	fetchCapturedFees(pid string, merchantWalletId int32) (int64, int64, int64, error)
+	fetchVelocityLimit(w wallet, currencyCode string) (velocityLimit, error)
+	fetchAuthorizationUsage(userId, currencyCode string) (authorizationUsage, error)
+	insertRiskDecision(d riskDecision) error
+
+	enqueueCaptureMessage(pid, userId string, amount int64, currencyCode string, legs []producer.CaptureLeg) error
+	enqueueRefundMessage(pid, userId string, amount int64, currencyCode, merchantUserId string, fee producer.CaptureFee) error
+}
+
+type mysqlStore struct {
+	db *sql.DB
+}
+
+func (s mysqlStore) begin(ctx context.Context) (unitOfWork, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+	return mysqlUnitOfWork{tx}, nil
+}
+
+// mysqlUnitOfWork runs a unit of work in a database transaction using the
+// same helpers as the rest of the service. Code that already holds a *sql.Tx
+// can wrap it to call helpers that take a unitOfWork.
+type mysqlUnitOfWork struct {
+	*sql.Tx
+}
+
+func (u mysqlUnitOfWork) lookupIdempotentResponse(userId, key, method string, req, resp proto.Message) (string, bool, error) {
+	return lookupIdempotentResponse(u.Tx, userId, key, method, req, resp)
+}
+
+func (u mysqlUnitOfWork) saveIdempotentResponse(userId, key, method, fingerprint string, resp proto.Message) error {
+	return saveIdempotentResponse(u.Tx, userId, key, method, fingerprint, resp)
+}
+
+func (u mysqlUnitOfWork) fetchWallet(userId string) (wallet, error) {
+	return fetchWallet(u.Tx, userId)
+}
+
+func (u mysqlUnitOfWork) fetchWalletWithWalletId(walletId int32) (wallet, error) {
+	return fetchWalletWithWalletId(u.Tx, walletId)
+}
+
+func (u mysqlUnitOfWork) fetchAccount(walletId int32, accountType, currencyCode string) (account, error) {
+	return fetchAccount(u.Tx, walletId, accountType, currencyCode)
+}
+
+func (u mysqlUnitOfWork) transfer(srcAccount, dstAccount account, amount int64) error {
+	return transfer(u.Tx, srcAccount, dstAccount, amount)
+}
+
+func (u mysqlUnitOfWork) insertTransaction(t transaction) error {
+	return insertTransaction(u.Tx, t)
+}
+
+func (u mysqlUnitOfWork) fetchTransaction(pid string) (transaction, error) {
+	return fetchTransaction(u.Tx, pid)
+}
+
+func (u mysqlUnitOfWork) insertPayment(p payment) error {
+	return insertPayment(u.Tx, p)
+}
+
+func (u mysqlUnitOfWork) fetchPayment(pid string) (payment, error) {
+	return fetchPayment(u.Tx, pid)
+}
+
+func (u mysqlUnitOfWork) updatePayment(p payment) error {
+	return updatePayment(u.Tx, p)
+}
+
+func (u mysqlUnitOfWork) fetchFxQuote(quoteId string) (fxQuote, error) {
+	return fetchFxQuote(u.Tx, quoteId)
+}
+
+func (u mysqlUnitOfWork) useFxQuote(quote fxQuote, pid, currencyCode string, amount int64) error {
+	return useFxQuote(u.Tx, quote, pid, currencyCode, amount)
+}
+
+func (u mysqlUnitOfWork) fetchFeeSchedule(merchantWalletId int32, currencyCode string) (feeSchedule, error) {
+	return fetchFeeSchedule(u.Tx, merchantWalletId, currencyCode)
+}
+
+func (u mysqlUnitOfWork) fetchCapturedFees(pid string, merchantWalletId int32) (int64, int64, int64, error) {
+	return fetchCapturedFees(u.Tx, pid, merchantWalletId)
+}
+
+func (u mysqlUnitOfWork) fetchVelocityLimit(w wallet, currencyCode string) (velocityLimit, error) {
+	return fetchVelocityLimit(u.Tx, w, currencyCode)
+}
+
+func (u mysqlUnitOfWork) fetchAuthorizationUsage(userId, currencyCode string) (authorizationUsage, error) {
+	return fetchAuthorizationUsage(u.Tx, userId, currencyCode)
+}
+
+func (u mysqlUnitOfWork) insertRiskDecision(d riskDecision) error {
+	return insertRiskDecision(u.Tx, d)
+}
+
+func (u mysqlUnitOfWork) enqueueCaptureMessage(pid, userId string, amount int64, currencyCode string, legs []producer.CaptureLeg) error {
+	return producer.EnqueueCaptureMessage(u.Tx, pid, userId, amount, currencyCode, legs)
+}
+
+func (u mysqlUnitOfWork) enqueueRefundMessage(pid, userId string, amount int64, currencyCode, merchantUserId string, fee producer.CaptureFee) error {
+	return producer.EnqueueRefundMessage(u.Tx, pid, userId, amount, currencyCode, merchantUserId, fee)
+}
@@ -3,27 +3,41 @@ package mm
 type wallet struct {
 	ID         int32  `json:"id"`
 	UserId     string `json:"user_id"`
-	walletType string `json:"wallet_type"`
+	walletType string
+	status     string
+	createdAt  int64 // unix seconds
 }
 
 type account struct {
-	ID          int32  `json:"id"`
-	cents       int64  `json:"cents"`
-	accountType string `json:"account_type"`
-	walletID    int32  `json:"wallet_id"`
+	ID          int32 `json:"id"`
+	cents       int64
+	accountType string
+	currency    string
+	walletID    int32
 }
 
 type transaction struct {
-	ID                       int32  `json:"id"`
-	pid                      string `json:"pid"`
-	srcUserId                string `json:"src_user_id"`
-	dstUserId                string `json:"dst_user_id"`
-	srcAccountWalletId       int32  `json:"src_account_wallet_id"`
-	dstAccountWalletId       int32  `json:"dst_account_wallet_id"`
-	srcAccountId             int32  `json:"src_account_id"`
-	dstAccountId             int32  `json:"dst_account_id"`
-	srcAccountType           string `json:"src_account_type"`
-	dstAccountType           string `json:"dst_account_type"`
-	finalDstMerchantWalletId int32  `json:"final_dst_merchant_wallet_id"`
-	amount                   int64  `json:"amount"`
+	ID                       int32 `json:"id"`
+	pid                      string
+	transactionType          string
+	captureId                string
+	srcUserId                string
+	dstUserId                string
+	srcAccountWalletId       int32
+	dstAccountWalletId       int32
+	srcAccountId             int32
+	dstAccountId             int32
+	srcAccountType           string
+	dstAccountType           string
+	finalDstMerchantWalletId int32
+	amount                   int64
+	currency                 string
+	convertedAmount          int64
+	convertedCurrency        string
+	fxQuoteId                string
+	fxRate                   string
+	fxMidRate                string
+	fxSpreadAmount           int64
+	feeAmount                int64
+	memo                     string
 }
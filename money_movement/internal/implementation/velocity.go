@@ -0,0 +1,215 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/MikePham0630/gomicro/internal/currency"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Reasons reported in the ErrorInfo of a rejected authorization or transfer
+const (
+	reasonSingleLimit  = "SINGLE_LIMIT_EXCEEDED"
+	reasonDailyLimit   = "DAILY_LIMIT_EXCEEDED"
+	reasonMonthlyLimit = "MONTHLY_LIMIT_EXCEEDED"
+	reasonHourlyCount  = "HOURLY_COUNT_EXCEEDED"
+
+	reasonDailyTransferLimit = "DAILY_TRANSFER_LIMIT_EXCEEDED"
+)
+
+const (
+	// Wallet overrides are stored with an empty wallet_type and wallet type
+	// defaults with a zero wallet_id.
+	upsertVelocityLimitQuery = `INSERT INTO velocity_limits (wallet_type, wallet_id, currency, max_single_cents, max_daily_cents, max_monthly_cents, max_hourly_count, max_daily_transfer_cents) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE max_single_cents = VALUES(max_single_cents), max_daily_cents = VALUES(max_daily_cents), max_monthly_cents = VALUES(max_monthly_cents), max_hourly_count = VALUES(max_hourly_count), max_daily_transfer_cents = VALUES(max_daily_transfer_cents)`
+	selectVelocityLimitQuery = `SELECT max_single_cents, max_daily_cents, max_monthly_cents, max_hourly_count, max_daily_transfer_cents FROM velocity_limits
+	WHERE currency = ? AND ((wallet_type = '' AND wallet_id = ?) OR (wallet_type = ? AND wallet_id = 0))
+	ORDER BY wallet_id DESC LIMIT 1`
+
+	// selectAuthorizationUsageQuery is a locking read so that concurrent
+	// authorizations by the same customer cannot both pass a limit. Increments
+	// add to the amounts but are not new authorizations. Amounts voided,
+	// expired or released by a final capture are taken off again as of when
+	// their payment was authorized; the hourly count still includes them.
+	selectAuthorizationUsageQuery = `SELECT COALESCE(SUM(CASE WHEN t.transaction_type IN ('AUTHORIZE', 'INCREMENT') AND t.created_at >= CURDATE() THEN t.amount
+		WHEN t.transaction_type NOT IN ('AUTHORIZE', 'INCREMENT') AND p.created_at >= CURDATE() THEN -t.amount ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN t.transaction_type IN ('AUTHORIZE', 'INCREMENT') AND t.created_at >= DATE_FORMAT(CURDATE(), '%Y-%m-01') THEN t.amount
+		WHEN t.transaction_type NOT IN ('AUTHORIZE', 'INCREMENT') AND p.created_at >= DATE_FORMAT(CURDATE(), '%Y-%m-01') THEN -t.amount ELSE 0 END), 0),
+	COUNT(CASE WHEN t.transaction_type = 'AUTHORIZE' AND t.created_at >= NOW() - INTERVAL 1 HOUR THEN 1 END)
+	FROM transactions t JOIN payments p ON p.pid = t.pid
+	WHERE t.src_user_id = ? AND t.transaction_type IN ('AUTHORIZE', 'INCREMENT', 'VOID', 'EXPIRE', 'RELEASE') AND t.currency = ?
+	AND t.created_at >= LEAST(DATE_FORMAT(CURDATE(), '%Y-%m-01'), NOW() - INTERVAL 1 HOUR) FOR UPDATE OF t`
+)
+
+// velocityLimit caps a customer's authorizations and P2P transfers in one
+// currency. A wallet override replaces the default of its wallet type as a
+// whole. Zero means no limit.
+type velocityLimit struct {
+	maxSingleCents        int64
+	maxDailyCents         int64
+	maxMonthlyCents       int64
+	maxHourlyCount        int64
+	maxDailyTransferCents int64
+}
+
+// authorizationUsage is what a customer authorized and still holds or spent
+// so far today and this calendar month, and how many authorizations they made
+// in the last hour.
+type authorizationUsage struct {
+	dailyCents   int64
+	monthlyCents int64
+	hourlyCount  int64
+}
+
+func (impl *Implementation) SetVelocityLimit(ctx context.Context, velocityLimitPayload *pb.VelocityLimit) (*emptypb.Empty, error) {
+	if err := callerFromContext(ctx).requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	if (velocityLimitPayload.GetWalletType() == "") == (velocityLimitPayload.GetUserId() == "") {
+		return nil, status.Errorf(codes.InvalidArgument, "exactly one of wallet type and user id is required")
+	}
+	if err := currency.Validate(velocityLimitPayload.GetCurrency()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if velocityLimitPayload.GetMaxSingleCents() < 0 || velocityLimitPayload.GetMaxDailyCents() < 0 || velocityLimitPayload.GetMaxMonthlyCents() < 0 || velocityLimitPayload.GetMaxHourlyCount() < 0 || velocityLimitPayload.GetMaxDailyTransferCents() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limits must not be negative")
+	}
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	walletType := velocityLimitPayload.GetWalletType()
+	var walletId int32
+	if walletType != "" {
+		if _, ok := walletAccountTypes[walletType]; !ok {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported wallet type: %s", walletType)
+		}
+	} else {
+		w, err := fetchWallet(tx, velocityLimitPayload.GetUserId())
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, err
+		}
+		walletId = w.ID
+	}
+
+	stmt, err := tx.Prepare(upsertVelocityLimitQuery)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	_, err = stmt.Exec(walletType, walletId, velocityLimitPayload.GetCurrency(), velocityLimitPayload.GetMaxSingleCents(), velocityLimitPayload.GetMaxDailyCents(), velocityLimitPayload.GetMaxMonthlyCents(), velocityLimitPayload.GetMaxHourlyCount(), velocityLimitPayload.GetMaxDailyTransferCents())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, dbError("failed to store velocity limit", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// checkVelocity returns a ResourceExhausted error carrying an ErrorInfo if
+// authorizing amount would break limit.
+func checkVelocity(limit velocityLimit, usage authorizationUsage, amount int64, currencyCode string) error {
+	switch {
+	case limit.maxSingleCents > 0 && amount > limit.maxSingleCents:
+		return velocityError(reasonSingleLimit, "authorization of %d %s exceeds the single authorization limit of %d", amount, currencyCode, limit.maxSingleCents, 0)
+	case limit.maxDailyCents > 0 && usage.dailyCents+amount > limit.maxDailyCents:
+		return velocityError(reasonDailyLimit, "authorization of %d %s exceeds the daily limit of %d", amount, currencyCode, limit.maxDailyCents, usage.dailyCents)
+	case limit.maxMonthlyCents > 0 && usage.monthlyCents+amount > limit.maxMonthlyCents:
+		return velocityError(reasonMonthlyLimit, "authorization of %d %s exceeds the monthly limit of %d", amount, currencyCode, limit.maxMonthlyCents, usage.monthlyCents)
+	case limit.maxHourlyCount > 0 && usage.hourlyCount+1 > limit.maxHourlyCount:
+		return velocityError(reasonHourlyCount, "authorization of %d %s exceeds the limit of %d authorizations per hour", amount, currencyCode, limit.maxHourlyCount, usage.hourlyCount)
+	}
+	return nil
+}
+
+// checkIncrementVelocity is checkVelocity for raising an authorization of
+// authorized by amount: the single authorization limit caps the new total and
+// the increment does not count as another authorization in the hour.
+func checkIncrementVelocity(limit velocityLimit, usage authorizationUsage, authorized, amount int64, currencyCode string) error {
+	if limit.maxSingleCents > 0 && authorized+amount > limit.maxSingleCents {
+		return velocityError(reasonSingleLimit, "authorization of %d %s exceeds the single authorization limit of %d", authorized+amount, currencyCode, limit.maxSingleCents, authorized)
+	}
+	limit.maxSingleCents, limit.maxHourlyCount = 0, 0
+	return checkVelocity(limit, usage, amount, currencyCode)
+}
+
+func velocityError(reason, format string, amount int64, currencyCode string, limit, used int64) error {
+	st := status.Newf(codes.ResourceExhausted, "%s: "+format, reason, amount, currencyCode, limit)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			"amount":   strconv.FormatInt(amount, 10),
+			"currency": currencyCode,
+			"limit":    strconv.FormatInt(limit, 10),
+			"used":     strconv.FormatInt(used, 10),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// fetchVelocityLimit returns the wallet's override for currencyCode, or the
+// default of its wallet type. Wallets with neither have no limits.
+func fetchVelocityLimit(tx *sql.Tx, w wallet, currencyCode string) (velocityLimit, error) {
+	var l velocityLimit
+	stmt, err := tx.Prepare(selectVelocityLimitQuery)
+	if err != nil {
+		return l, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = stmt.QueryRow(currencyCode, w.ID, w.walletType).Scan(&l.maxSingleCents, &l.maxDailyCents, &l.maxMonthlyCents, &l.maxHourlyCount, &l.maxDailyTransferCents)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return velocityLimit{}, nil
+		}
+		return l, dbError("failed to query velocity limit", err)
+	}
+	return l, nil
+}
+
+func fetchAuthorizationUsage(tx *sql.Tx, userId, currencyCode string) (authorizationUsage, error) {
+	var u authorizationUsage
+	stmt, err := tx.Prepare(selectAuthorizationUsageQuery)
+	if err != nil {
+		return u, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	err = stmt.QueryRow(userId, currencyCode).Scan(&u.dailyCents, &u.monthlyCents, &u.hourlyCount)
+	if err != nil {
+		return u, dbError("failed to query authorization usage", err)
+	}
+	return u, nil
+}
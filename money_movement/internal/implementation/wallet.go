@@ -0,0 +1,275 @@
+package mm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/MikePham0630/gomicro/internal/currency"
+	pb "github.com/MikePham0630/gomicro/proto"
+	"github.com/go-sql-driver/mysql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	walletTypeCustomer = "CUSTOMER"
+	walletTypeMerchant = "MERCHANT"
+
+	walletStatusActive = "ACTIVE"
+	walletStatusClosed = "CLOSED"
+
+	defaultWalletCurrency = "USD"
+)
+
+const (
+	insertWalletQuery     = "INSERT INTO wallets (user_id, wallet_type) VALUES (?, ?)"
+	insertAccountQuery    = "INSERT INTO accounts (cents, account_type, currency, wallet_id) VALUES (0, ?, ?, ?)"
+	closeWalletQuery      = "UPDATE wallets SET status = ?, closed_at = NOW() WHERE id = ?"
+	countOpenPaymentQuery = "SELECT COUNT(*) FROM payments p WHERE p.status IN ('AUTHORIZED', 'PARTIALLY_CAPTURED') AND (p.customer_wallet_id = ? OR p.merchant_wallet_id = ? OR EXISTS (SELECT 1 FROM payment_legs l WHERE l.pid = p.pid AND l.merchant_wallet_id = ?))"
+)
+
+// walletAccountTypes lists the accounts every wallet type needs, per currency.
+var walletAccountTypes = map[string][]string{
+	walletTypeCustomer: {"DEFAULT", "PAYMENT"},
+	walletTypeMerchant: {"INCOMING", "SETTLED"},
+}
+
+func (impl *Implementation) CreateWallet(ctx context.Context, createWalletPayload *pb.CreateWalletPayload) (*pb.Wallet, error) {
+	c := callerFromContext(ctx)
+	if err := c.requireOwner(createWalletPayload.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	// Customers get customer wallets and merchants merchant wallets, only
+	// admins can create either
+	if !c.isAdmin() && c.role != createWalletPayload.GetWalletType() {
+		return nil, status.Errorf(codes.PermissionDenied, "caller with role %s may not create a %s wallet", c.role, createWalletPayload.GetWalletType())
+	}
+
+	if createWalletPayload.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user id is required")
+	}
+
+	accountTypes, ok := walletAccountTypes[createWalletPayload.GetWalletType()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported wallet type: %s", createWalletPayload.GetWalletType())
+	}
+
+	currencies := createWalletPayload.GetCurrencies()
+	if len(currencies) == 0 {
+		currencies = []string{defaultWalletCurrency}
+	}
+	seen := make(map[string]bool, len(currencies))
+	for _, code := range currencies {
+		if err := currency.Validate(code); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		if seen[code] {
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate currency: %s", code)
+		}
+		seen[code] = true
+	}
+
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	walletId, err := insertWallet(tx, createWalletPayload.GetUserId(), createWalletPayload.GetWalletType())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	for _, code := range currencies {
+		for _, accountType := range accountTypes {
+			err = insertAccount(tx, walletId, accountType, code)
+			if err != nil {
+				rollbackErr := tx.Rollback()
+				if rollbackErr != nil {
+					return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+				}
+				return nil, err
+			}
+		}
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &pb.Wallet{
+		WalletId:   walletId,
+		UserId:     createWalletPayload.GetUserId(),
+		WalletType: createWalletPayload.GetWalletType(),
+		Status:     walletStatusActive,
+	}, nil
+}
+
+func (impl *Implementation) CloseWallet(ctx context.Context, closeWalletPayload *pb.CloseWalletPayload) (*emptypb.Empty, error) {
+	if err := callerFromContext(ctx).requireOwner(closeWalletPayload.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	return retryOnConflict(ctx, func() (*emptypb.Empty, error) {
+		return impl.closeWallet(closeWalletPayload)
+	})
+}
+
+func (impl *Implementation) closeWallet(closeWalletPayload *pb.CloseWalletPayload) (*emptypb.Empty, error) {
+	//Begin a transaction
+	tx, err := impl.db.Begin()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to begin transaction: %v", err)
+	}
+
+	w, err := fetchWallet(tx, closeWalletPayload.GetUserId())
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	if _, ok := walletAccountTypes[w.walletType]; !ok || w.status != walletStatusActive {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "wallet %d cannot be closed", w.ID)
+	}
+
+	// Lock the accounts so no transfer can land between the check and the close
+	accounts, err := lockWalletAccounts(tx, w.ID)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	for _, a := range accounts {
+		if a.cents != 0 {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+			}
+			return nil, status.Errorf(codes.FailedPrecondition, "wallet %d still holds %d in its %s %s account", w.ID, a.cents, a.currency, a.accountType)
+		}
+	}
+
+	// A merchant with open authorizations would still receive captures
+	openPayments, err := countOpenPayments(tx, w.ID)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	if openPayments > 0 {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "wallet %d has %d open payments", w.ID, openPayments)
+	}
+
+	stmt, err := tx.Prepare(closeWalletQuery)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to prepare close wallet statement: %v", err)
+	}
+
+	_, err = stmt.Exec(walletStatusClosed, w.ID)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rollback transaction: %v", rollbackErr)
+		}
+		return nil, dbError("failed to close wallet", err)
+	}
+
+	//commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to commit transaction: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func insertWallet(tx *sql.Tx, userId, walletType string) (int32, error) {
+	stmt, err := tx.Prepare(insertWalletQuery)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to prepare insert wallet statement: %v", err)
+	}
+
+	res, err := stmt.Exec(userId, walletType)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return 0, status.Errorf(codes.AlreadyExists, "wallet already exists for user: %s", userId)
+		}
+		return 0, dbError("failed to insert wallet", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to get wallet id: %v", err)
+	}
+
+	return int32(id), nil
+}
+
+func insertAccount(tx *sql.Tx, walletId int32, accountType, currencyCode string) error {
+	stmt, err := tx.Prepare(insertAccountQuery)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to prepare insert account statement: %v", err)
+	}
+
+	_, err = stmt.Exec(accountType, currencyCode, walletId)
+	if err != nil {
+		return dbError("failed to insert account", err)
+	}
+
+	return nil
+}
+
+func lockWalletAccounts(tx *sql.Tx, walletId int32) ([]account, error) {
+	stmt, err := tx.Prepare(selectWalletAccountsQuery + " FOR UPDATE")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+	return queryAccounts(stmt, walletId)
+}
+
+// countOpenPayments counts the payments still holding funds that walletId takes
+// part in, as customer, merchant or merchant of any leg of a split payment.
+func countOpenPayments(tx *sql.Tx, walletId int32) (int, error) {
+	stmt, err := tx.Prepare(countOpenPaymentQuery)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to prepare statement: %v", err)
+	}
+
+	var count int
+	err = stmt.QueryRow(walletId, walletId, walletId).Scan(&count)
+	if err != nil {
+		return 0, dbError("failed to count open payments", err)
+	}
+
+	return count, nil
+}
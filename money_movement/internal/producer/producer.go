@@ -1,92 +1,269 @@
 package producer
 
 import (
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
-	"os"
-	"sync"
 	"time"
 
-	"github.com/IBM/sarama"
+	"github.com/MikePham0630/gomicro/internal/currency"
 )
 
 const (
-	emailTopic  = "email"
-	ledgerTopic = "ledger"
+	emailTopic      = "email"
+	ledgerTopic     = "ledger"
+	settlementTopic = "settlement"
 )
 
+const (
+	emailTypeCapture    = "capture"
+	emailTypeRefund     = "refund"
+	emailTypeExpired    = "expired"
+	emailTypeDeposit    = "deposit"
+	emailTypeWithdrawal = "withdrawal"
+	emailTypeSent       = "transfer_sent"
+	emailTypeReceived   = "transfer_received"
+)
+
+// Operations recorded by the ledger service for each kind of event
+const (
+	LedgerOperationCapture    = "DEBIT"
+	LedgerOperationRefund     = "CREDIT"
+	LedgerOperationExpire     = "EXPIRE"
+	LedgerOperationDeposit    = "DEPOSIT"
+	LedgerOperationWithdrawal = "WITHDRAWAL"
+)
+
+const insertOutboxQuery = "INSERT INTO outbox (topic, msg_key, payload) VALUES (?, ?, ?)"
+
 type EmailMsg struct {
-	OserId string `json:"order_id"`
-	UserId string `json:"user_id"`
+	OserId   string `json:"order_id"`
+	UserId   string `json:"user_id"`
+	Type     string `json:"type"` // e.g., "capture", "refund", "expired"
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"` // ISO 4217 code
+	Exponent int    `json:"exponent"` // minor-unit exponent of Currency
+	Memo     string `json:"memo,omitempty"`
 }
 
 type LedgerMsg struct {
 	OrderId   string `json:"order_id"`
 	UserId    string `json:"user_id"`
 	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`  // ISO 4217 code
 	Operation string `json:"operation"` // e.g., "capture", "refund"
 	Date      string `json:"date"`      // ISO 8601 format
+
+	// Merchant side of a capture or refund, in FeeCurrency
+	MerchantUserId string `json:"merchant_user_id,omitempty"`
+	Gross          int64  `json:"gross,omitempty"`
+	Fee            int64  `json:"fee,omitempty"`
+	Net            int64  `json:"net,omitempty"`
+	FeeCurrency    string `json:"fee_currency,omitempty"`
 }
 
-func SendCaptureMessage(pid, userId string, amount int64) {
-	sarama.Logger = log.New(os.Stdout, "[sarama] ", log.LstdFlags)
-	// Create sync producer
-	producer, err := sarama.NewSyncProducer([]string{"my-cluster-kafka-bootstrap:9092"}, sarama.NewConfig())
+// CaptureFee splits what a merchant was credited for a capture into the fee
+// kept by the house and the net amount. For a refund it is what the merchant
+// gives back: the gross refunded, the part of the fee returned to it and the
+// net it pays.
+type CaptureFee struct {
+	Gross    int64
+	Fee      int64
+	Net      int64
+	Currency string
+}
+
+// CaptureLeg is what one merchant was paid by a capture. Amount is in the
+// customer's currency; split payments have one leg per merchant.
+type CaptureLeg struct {
+	MerchantUserId string
+	Amount         int64
+	Fee            CaptureFee
+}
+
+type SettlementMsg struct {
+	SettlementId   string   `json:"settlement_id"`
+	MerchantUserId string   `json:"merchant_user_id"`
+	Amount         int64    `json:"amount"`
+	Currency       string   `json:"currency"` // ISO 4217 code
+	Pids           []string `json:"pids"`
+	Cutoff         string   `json:"cutoff"` // RFC 3339
+}
+
+// EnqueueCaptureMessage writes the capture email and ledger events to the outbox
+// as part of tx: one email to the customer for the whole capture and one ledger
+// event per merchant leg. The Relay publishes them to Kafka once tx has
+// committed.
+func EnqueueCaptureMessage(tx *sql.Tx, pid, userId string, amount int64, currencyCode string, legs []CaptureLeg) error {
+	log.Printf("Enqueueing capture message: pid=%s, userId=%s, amount=%d %s, legs=%d", pid, userId, amount, currencyCode, len(legs))
+	exponent, ok := currency.Exponent(currencyCode)
+	if !ok {
+		return fmt.Errorf("unsupported currency %q", currencyCode)
+	}
+
+	err := enqueue(tx, EmailMsg{OserId: pid, UserId: userId, Type: emailTypeCapture, Amount: amount, Currency: currencyCode, Exponent: exponent}, emailTopic, pid)
 	if err != nil {
-		log.Println(err)
-		return
+		return err
 	}
 
-	defer func() {
-		if err := producer.Close(); err != nil {
-			log.Println("Failed to close producer:", err)
+	for _, leg := range legs {
+		err = enqueue(tx, LedgerMsg{
+			OrderId:   pid,
+			UserId:    userId,
+			Amount:    leg.Amount,
+			Currency:  currencyCode,
+			Operation: LedgerOperationCapture,
+			Date:      time.Now().Format("2006-01-02"),
+
+			MerchantUserId: leg.MerchantUserId,
+			Gross:          leg.Fee.Gross,
+			Fee:            leg.Fee.Fee,
+			Net:            leg.Fee.Net,
+			FeeCurrency:    leg.Fee.Currency,
+		}, ledgerTopic, pid)
+		if err != nil {
+			return err
 		}
-	}()
+	}
+
+	return nil
+}
+
+// EnqueueRefundMessage notifies the customer of a refund of amount and records
+// it in the ledger along with the merchant side of the refund.
+func EnqueueRefundMessage(tx *sql.Tx, pid, userId string, amount int64, currencyCode, merchantUserId string, fee CaptureFee) error {
+	log.Printf("Enqueueing refund message: pid=%s, userId=%s, amount=%d %s, merchant=%s", pid, userId, amount, currencyCode, merchantUserId)
+	exponent, ok := currency.Exponent(currencyCode)
+	if !ok {
+		return fmt.Errorf("unsupported currency %q", currencyCode)
+	}
+
+	err := enqueue(tx, EmailMsg{OserId: pid, UserId: userId, Type: emailTypeRefund, Amount: amount, Currency: currencyCode, Exponent: exponent}, emailTopic, pid)
+	if err != nil {
+		return err
+	}
+
+	return enqueue(tx, LedgerMsg{
+		OrderId:   pid,
+		UserId:    userId,
+		Amount:    amount,
+		Currency:  currencyCode,
+		Operation: LedgerOperationRefund,
+		Date:      time.Now().Format("2006-01-02"),
+
+		MerchantUserId: merchantUserId,
+		Gross:          fee.Gross,
+		Fee:            fee.Fee,
+		Net:            fee.Net,
+		FeeCurrency:    fee.Currency,
+	}, ledgerTopic, pid)
+}
+
+func EnqueueExpiryMessage(tx *sql.Tx, pid, userId string, amount int64, currencyCode string) error {
+	log.Printf("Enqueueing expiry message: pid=%s, userId=%s, amount=%d %s", pid, userId, amount, currencyCode)
+	return enqueueMessages(tx, pid, userId, amount, currencyCode, emailTypeExpired, LedgerOperationExpire)
+}
+
+func EnqueueDepositMessage(tx *sql.Tx, fundingId, userId string, amount int64, currencyCode string) error {
+	log.Printf("Enqueueing deposit message: fundingId=%s, userId=%s, amount=%d %s", fundingId, userId, amount, currencyCode)
+	return enqueueMessages(tx, fundingId, userId, amount, currencyCode, emailTypeDeposit, LedgerOperationDeposit)
+}
+
+func EnqueueWithdrawalMessage(tx *sql.Tx, fundingId, userId string, amount int64, currencyCode string) error {
+	log.Printf("Enqueueing withdrawal message: fundingId=%s, userId=%s, amount=%d %s", fundingId, userId, amount, currencyCode)
+	return enqueueMessages(tx, fundingId, userId, amount, currencyCode, emailTypeWithdrawal, LedgerOperationWithdrawal)
+}
+
+// EnqueueTransferMessages notifies both the sender and the recipient of a
+// peer-to-peer transfer.
+func EnqueueTransferMessages(tx *sql.Tx, transferId, fromUserId, toUserId string, amount int64, currencyCode, memo string) error {
+	log.Printf("Enqueueing transfer messages: transferId=%s, from=%s, to=%s, amount=%d %s", transferId, fromUserId, toUserId, amount, currencyCode)
+	exponent, ok := currency.Exponent(currencyCode)
+	if !ok {
+		return fmt.Errorf("unsupported currency %q", currencyCode)
+	}
+
+	for _, msg := range []EmailMsg{
+		{OserId: transferId, UserId: fromUserId, Type: emailTypeSent, Amount: amount, Currency: currencyCode, Exponent: exponent, Memo: memo},
+		{OserId: transferId, UserId: toUserId, Type: emailTypeReceived, Amount: amount, Currency: currencyCode, Exponent: exponent, Memo: memo},
+	} {
+		err := enqueue(tx, msg, emailTopic, transferId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func EnqueueSettlementMessage(tx *sql.Tx, settlementId, merchantUserId string, amount int64, currencyCode string, pids []string, cutoff time.Time) error {
+	log.Printf("Enqueueing settlement message: settlementId=%s, merchantUserId=%s, amount=%d %s", settlementId, merchantUserId, amount, currencyCode)
+	msg := SettlementMsg{
+		SettlementId:   settlementId,
+		MerchantUserId: merchantUserId,
+		Amount:         amount,
+		Currency:       currencyCode,
+		Pids:           pids,
+		Cutoff:         cutoff.UTC().Format(time.RFC3339),
+	}
+	return enqueue(tx, msg, settlementTopic, merchantUserId)
+}
+
+// EnqueueLedgerMessage writes a single ledger event to the outbox, without the
+// matching email. It is used to republish events the ledger never recorded.
+func EnqueueLedgerMessage(tx *sql.Tx, msg LedgerMsg) error {
+	log.Printf("Enqueueing ledger message: orderId=%s, operation=%s, amount=%d %s", msg.OrderId, msg.Operation, msg.Amount, msg.Currency)
+	return enqueue(tx, msg, ledgerTopic, msg.OrderId)
+}
+
+func enqueueMessages(tx *sql.Tx, pid, userId string, amount int64, currencyCode, emailType, operation string) error {
+	exponent, ok := currency.Exponent(currencyCode)
+	if !ok {
+		return fmt.Errorf("unsupported currency %q", currencyCode)
+	}
 
 	emailMsg := EmailMsg{
-		OserId: pid,
-		UserId: userId,
+		OserId:   pid,
+		UserId:   userId,
+		Type:     emailType,
+		Amount:   amount,
+		Currency: currencyCode,
+		Exponent: exponent,
 	}
 
 	LedgerMsg := LedgerMsg{
 		OrderId:   pid,
 		UserId:    userId,
 		Amount:    amount,
-		Operation: "DEBIT",
+		Currency:  currencyCode,
+		Operation: operation,
 		Date:      time.Now().Format("2006-01-02"),
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go sendMsg(producer, emailMsg, emailTopic, &wg)
-	go sendMsg(producer, LedgerMsg, ledgerTopic, &wg)
-	wg.Wait()
+	err := enqueue(tx, emailMsg, emailTopic, pid)
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Sending capture message: pid=%s, userId=%s, amount=%d", pid, userId, amount)
-	// Example: producer.Publish("capture_topic", message)
+	return enqueue(tx, LedgerMsg, ledgerTopic, pid)
 }
 
-func sendMsg[T EmailMsg | LedgerMsg](producer sarama.SyncProducer, msg T, topic string, wg *sync.WaitGroup) {
+func enqueue[T EmailMsg | LedgerMsg | SettlementMsg](tx *sql.Tx, msg T, topic, key string) error {
 	stringMsg, err := json.Marshal(msg)
 	if err != nil {
-		log.Printf("Failed to marshal message: %v", err)
-		return
+		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	message := &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.StringEncoder(stringMsg),
+	stmt, err := tx.Prepare(insertOutboxQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare outbox statement: %w", err)
 	}
 
-	// Send the message
-
-	partition, offset, err := producer.SendMessage(message)
+	_, err = stmt.Exec(topic, key, stringMsg)
 	if err != nil {
-		log.Printf("Failed to send message to topic %s: %v", topic, err)
-		return
+		return fmt.Errorf("failed to insert outbox message for topic %s: %w", topic, err)
 	}
 
-	log.Printf("Message sent to topic %s at partition %d with offset %d", topic, partition, offset)
-	wg.Done()
+	return nil
 }
@@ -0,0 +1,223 @@
+package producer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+)
+
+const (
+	// Pending rows are read without locking them, so that transactions
+	// writing to the outbox never wait on Kafka. The lease keeps a single
+	// replica relaying at a time, which preserves ordering.
+	acquireRelayLeaseQuery   = "UPDATE outbox_relay_lease SET holder = ?, expires_at = CURRENT_TIMESTAMP + INTERVAL ? SECOND WHERE id = 1 AND (holder = ? OR expires_at < CURRENT_TIMESTAMP)"
+	selectRelayLeaseQuery    = "SELECT holder FROM outbox_relay_lease WHERE id = 1"
+	selectPendingOutboxQuery = "SELECT id, topic, msg_key, payload FROM outbox WHERE sent_at IS NULL ORDER BY id LIMIT ?"
+	markOutboxSentQuery      = "UPDATE outbox SET sent_at = CURRENT_TIMESTAMP, attempts = attempts + 1 WHERE id = ?"
+	markOutboxFailedQuery    = "UPDATE outbox SET attempts = attempts + 1 WHERE id = ?"
+
+	relayBatchSize     = 100
+	relayPollInterval  = time.Second
+	relayMinBackoff    = time.Second
+	relayMaxBackoff    = time.Minute
+	relayLeaseDuration = 30 * time.Second
+)
+
+type outboxMsg struct {
+	id      int64
+	topic   string
+	key     string
+	payload []byte
+}
+
+// Relay publishes pending outbox rows to Kafka in insertion order and marks
+// them sent. Delivery is at-least-once: a crash between publishing and
+// marking the row sent, or a lease that runs out in the middle of a batch,
+// republishes the row.
+type Relay struct {
+	db       *sql.DB
+	id       string // lease holder
+	brokers  []string
+	producer sarama.SyncProducer
+}
+
+func NewRelay(db *sql.DB) *Relay {
+	id := uuid.NewString()
+	if host, err := os.Hostname(); err == nil {
+		id = host + "-" + id
+	}
+	return &Relay{
+		db:      db,
+		id:      id,
+		brokers: []string{"my-cluster-kafka-bootstrap:9092"},
+	}
+}
+
+// Run publishes pending messages until ctx is done, backing off while Kafka
+// or the database is unavailable.
+func (r *Relay) Run(ctx context.Context) {
+	defer r.close()
+
+	backoff := relayMinBackoff
+	for {
+		wait := relayPollInterval
+		sent, err := r.publishPending()
+		if err != nil {
+			log.Printf("Failed to relay outbox messages, retrying in %s: %v", backoff, err)
+			wait = backoff
+			backoff = min(backoff*2, relayMaxBackoff)
+		} else {
+			backoff = relayMinBackoff
+			// Keep draining while there is a backlog
+			if sent == relayBatchSize {
+				wait = 0
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (r *Relay) connect() error {
+	if r.producer != nil {
+		return nil
+	}
+
+	sarama.Logger = log.New(os.Stdout, "[sarama] ", log.LstdFlags)
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+
+	producer, err := sarama.NewSyncProducer(r.brokers, config)
+	if err != nil {
+		return fmt.Errorf("failed to create producer: %w", err)
+	}
+	r.producer = producer
+	return nil
+}
+
+func (r *Relay) close() {
+	if r.producer == nil {
+		return
+	}
+	if err := r.producer.Close(); err != nil {
+		log.Println("Failed to close producer:", err)
+	}
+}
+
+// publishPending sends one batch of pending messages if this replica holds
+// the relay lease. No transaction is open while messages are sent; each row
+// is marked on its own once Kafka has accepted it, and a failed send stops the
+// batch to preserve ordering.
+func (r *Relay) publishPending() (int, error) {
+	leader, err := r.acquireLease()
+	if err != nil || !leader {
+		return 0, err
+	}
+
+	err = r.connect()
+	if err != nil {
+		return 0, err
+	}
+
+	msgs, err := fetchPending(r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var sendErr error
+	for _, msg := range msgs {
+		partition, offset, err := r.producer.SendMessage(&sarama.ProducerMessage{
+			Topic: msg.topic,
+			Key:   sarama.StringEncoder(msg.key),
+			Value: sarama.ByteEncoder(msg.payload),
+		})
+		if err != nil {
+			sendErr = fmt.Errorf("failed to send outbox message %d to topic %s: %w", msg.id, msg.topic, err)
+			_, err = r.db.Exec(markOutboxFailedQuery, msg.id)
+			if err != nil {
+				log.Printf("Failed to record attempt for outbox message %d: %v", msg.id, err)
+			}
+			break
+		}
+
+		_, err = r.db.Exec(markOutboxSentQuery, msg.id)
+		if err != nil {
+			sendErr = fmt.Errorf("failed to mark outbox message %d sent: %w", msg.id, err)
+			break
+		}
+		log.Printf("Message %d sent to topic %s at partition %d with offset %d", msg.id, msg.topic, partition, offset)
+		sent++
+	}
+
+	return sent, sendErr
+}
+
+// acquireLease takes or renews the relay lease and reports whether this
+// replica holds it. The lease row is the only row locked, and only for the
+// duration of this short transaction.
+func (r *Relay) acquireLease() (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	_, err = tx.Exec(acquireRelayLeaseQuery, r.id, int(relayLeaseDuration.Seconds()), r.id)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return false, fmt.Errorf("failed to rollback transaction: %w", rollbackErr)
+		}
+		return false, fmt.Errorf("failed to acquire relay lease: %w", err)
+	}
+
+	// A renewal within the same second changes nothing and reports no
+	// affected rows, so read the holder back instead
+	var holder string
+	err = tx.QueryRow(selectRelayLeaseQuery).Scan(&holder)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return false, fmt.Errorf("failed to rollback transaction: %w", rollbackErr)
+		}
+		return false, fmt.Errorf("failed to read relay lease: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return holder == r.id, nil
+}
+
+func fetchPending(db *sql.DB) ([]outboxMsg, error) {
+	rows, err := db.Query(selectPendingOutboxQuery, relayBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []outboxMsg
+	for rows.Next() {
+		var msg outboxMsg
+		if err := rows.Scan(&msg.id, &msg.topic, &msg.key, &msg.payload); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox row: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+
+	return msgs, nil
+}
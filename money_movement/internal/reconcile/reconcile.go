@@ -0,0 +1,386 @@
+// Package reconcile compares the events the ledger service recorded with the
+// transactions money_movement committed, so that lost or replayed Kafka
+// messages can be found and republished.
+package reconcile
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/producer"
+)
+
+const dateLayout = "2006-01-02"
+
+// Kinds of discrepancy between the two sides
+const (
+	KindMissing    = "missing"    // committed in money_movement, absent from the ledger
+	KindDuplicated = "duplicated" // recorded by the ledger more often than it happened
+	KindMismatched = "mismatched" // recorded with a different amount or currency
+	KindUnexpected = "unexpected" // recorded by the ledger with no matching transaction
+)
+
+const (
+	// selectTransactionsQuery returns the transactions that publish a ledger
+	// event. Withdrawals only publish one once the payout has completed.
+	selectTransactionsQuery = `SELECT t.id, t.pid, t.transaction_type, t.src_user_id, t.dst_user_id, t.amount, t.currency, t.converted_amount, t.converted_currency, t.fee_amount, COALESCE(mw.user_id, ''), DATE_FORMAT(t.created_at, '%Y-%m-%d')
+	FROM transactions t LEFT JOIN fundings f ON f.funding_id = t.pid LEFT JOIN wallets mw ON mw.id = t.final_dst_merchant_wallet_id
+	WHERE t.transaction_type IN ('CAPTURE', 'REFUND', 'EXPIRE', 'DEPOSIT', 'WITHDRAWAL') AND (t.transaction_type <> 'WITHDRAWAL' OR f.status = 'COMPLETED')`
+	selectTransactionsByDateQuery = selectTransactionsQuery + " AND t.created_at >= ? AND t.created_at < ? ORDER BY t.id"
+	selectTransactionsByPidQuery  = selectTransactionsQuery + " AND t.pid = ? ORDER BY t.id"
+
+	selectLedgerQuery       = "SELECT id, order_id, user_id, amount, currency, operation, transaction_date FROM ledger"
+	selectLedgerByDateQuery = selectLedgerQuery + " WHERE transaction_date >= ? AND transaction_date < ? ORDER BY id"
+	selectLedgerByPidQuery  = selectLedgerQuery + " WHERE order_id = ? ORDER BY id"
+)
+
+// Entry is one ledger event, either expected from a money_movement
+// transaction or actually recorded by the ledger service.
+type Entry struct {
+	Id        int64  `json:"id"` // transactions.id or ledger.id
+	Pid       string `json:"pid"`
+	UserId    string `json:"user_id"`
+	Operation string `json:"operation"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Date      string `json:"date"`
+
+	// msg is the event money_movement published for this entry. It is only
+	// set on expected entries.
+	msg producer.LedgerMsg
+}
+
+type Discrepancy struct {
+	Kind      string `json:"kind"`
+	Pid       string `json:"pid"`
+	Operation string `json:"operation"`
+	Expected  *Entry `json:"expected,omitempty"`
+	Actual    *Entry `json:"actual,omitempty"`
+}
+
+// date is the day the discrepancy is reported under: the transaction's if
+// there is one, the ledger entry's otherwise.
+func (d Discrepancy) date() string {
+	if d.Expected != nil {
+		return d.Expected.Date
+	}
+	return d.Actual.Date
+}
+
+type Report struct {
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	Transactions  int           `json:"transactions"`
+	LedgerEntries int           `json:"ledger_entries"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Run compares both sides for every day from from up to and including to.
+//
+// money_movement dates a transaction by the database clock and the ledger an
+// event by the clock of the service that published it, so an event near
+// midnight can be dated a day apart on the two sides. Both sides are fetched a
+// day wider than asked for, so that such an event still finds its match, and
+// only discrepancies dated within the requested days are reported.
+func Run(mmDB, ledgerDB *sql.DB, from, to time.Time) (*Report, error) {
+	end := to.AddDate(0, 0, 1)
+	windowFrom, windowEnd := from.AddDate(0, 0, -1), end.AddDate(0, 0, 1)
+
+	expected, err := FetchTransactions(mmDB, windowFrom, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := FetchLedger(ledgerDB, windowFrom, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDate, endDate := from.Format(dateLayout), end.Format(dateLayout)
+	inRange := func(date string) bool {
+		return date >= fromDate && date < endDate
+	}
+
+	report := &Report{
+		From:          fromDate,
+		To:            to.Format(dateLayout),
+		Discrepancies: []Discrepancy{},
+	}
+	for _, e := range expected {
+		if inRange(e.Date) {
+			report.Transactions++
+		}
+	}
+	for _, e := range actual {
+		if inRange(e.Date) {
+			report.LedgerEntries++
+		}
+	}
+	for _, d := range Compare(expected, actual) {
+		if inRange(d.date()) {
+			report.Discrepancies = append(report.Discrepancies, d)
+		}
+	}
+
+	return report, nil
+}
+
+// FetchTransactions returns the ledger events money_movement published for
+// transactions created on the days in [from, end).
+func FetchTransactions(db *sql.DB, from, end time.Time) ([]Entry, error) {
+	return fetchTransactions(db, selectTransactionsByDateQuery, from.Format(dateLayout), end.Format(dateLayout))
+}
+
+func fetchTransactions(db *sql.DB, query string, args ...any) ([]Entry, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var (
+			e                                     Entry
+			transactionType, srcUserId, dstUserId string
+			merchantUserId                        string
+			amount, convertedAmount, feeAmount    int64
+			currencyCode, convertedCurrency       string
+		)
+		err = rows.Scan(&e.Id, &e.Pid, &transactionType, &srcUserId, &dstUserId, &amount, &currencyCode, &convertedAmount, &convertedCurrency, &feeAmount, &merchantUserId, &e.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+
+		// Mirror what each operation enqueued: the customer's side of the
+		// movement, in the customer's currency
+		e.UserId, e.Amount, e.Currency = srcUserId, amount, currencyCode
+		switch transactionType {
+		case "CAPTURE":
+			e.Operation = producer.LedgerOperationCapture
+		case "REFUND":
+			e.Operation = producer.LedgerOperationRefund
+			e.UserId, e.Amount, e.Currency = dstUserId, convertedAmount, convertedCurrency
+		case "EXPIRE":
+			e.Operation = producer.LedgerOperationExpire
+		case "DEPOSIT":
+			e.Operation = producer.LedgerOperationDeposit
+			e.UserId = dstUserId
+		case "WITHDRAWAL":
+			e.Operation = producer.LedgerOperationWithdrawal
+		}
+
+		e.msg = producer.LedgerMsg{
+			OrderId:   e.Pid,
+			UserId:    e.UserId,
+			Amount:    e.Amount,
+			Currency:  e.Currency,
+			Operation: e.Operation,
+			Date:      e.Date,
+		}
+		switch transactionType {
+		case "CAPTURE":
+			e.msg.MerchantUserId = merchantUserId
+			e.msg.Gross = convertedAmount
+			e.msg.Fee = feeAmount
+			e.msg.Net = convertedAmount - feeAmount
+			e.msg.FeeCurrency = convertedCurrency
+		case "REFUND":
+			// The merchant pays the refund back net of the fee returned to it
+			e.msg.MerchantUserId = merchantUserId
+			e.msg.Gross = amount
+			e.msg.Fee = feeAmount
+			e.msg.Net = amount - feeAmount
+			e.msg.FeeCurrency = currencyCode
+		}
+
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transactions: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FetchLedger returns the entries the ledger service recorded for dates in
+// [from, end).
+func FetchLedger(db *sql.DB, from, end time.Time) ([]Entry, error) {
+	return fetchLedger(db, selectLedgerByDateQuery, from.Format(dateLayout), end.Format(dateLayout))
+}
+
+func fetchLedger(db *sql.DB, query string, args ...any) ([]Entry, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		err = rows.Scan(&e.Id, &e.Pid, &e.UserId, &e.Amount, &e.Currency, &e.Operation, &e.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Compare matches expected and actual entries by pid and operation. Within a
+// pid and operation, entries with the same amount and currency match one to
+// one; a payment captured twice for the same amount expects two entries.
+func Compare(expected, actual []Entry) []Discrepancy {
+	type key struct{ pid, operation string }
+	groups := make(map[key][2][]Entry)
+	for _, e := range expected {
+		k := key{e.Pid, e.Operation}
+		g := groups[k]
+		g[0] = append(g[0], e)
+		groups[k] = g
+	}
+	for _, e := range actual {
+		k := key{e.Pid, e.Operation}
+		g := groups[k]
+		g[1] = append(g[1], e)
+		groups[k] = g
+	}
+
+	keys := make([]key, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pid != keys[j].pid {
+			return keys[i].pid < keys[j].pid
+		}
+		return keys[i].operation < keys[j].operation
+	})
+
+	discrepancies := []Discrepancy{}
+	for _, k := range keys {
+		exp, act := groups[k][0], groups[k][1]
+
+		var unmatchedExp []Entry
+		matched := make([]bool, len(act))
+		for _, e := range exp {
+			found := false
+			for i, a := range act {
+				if !matched[i] && sameAmount(e, a) {
+					matched[i], found = true, true
+					break
+				}
+			}
+			if !found {
+				unmatchedExp = append(unmatchedExp, e)
+			}
+		}
+
+		var unmatchedAct []Entry
+		for i, a := range act {
+			if matched[i] {
+				continue
+			}
+			if hasSameAmount(exp, a) {
+				discrepancies = append(discrepancies, Discrepancy{Kind: KindDuplicated, Pid: k.pid, Operation: k.operation, Actual: &a})
+				continue
+			}
+			unmatchedAct = append(unmatchedAct, a)
+		}
+
+		for len(unmatchedExp) > 0 && len(unmatchedAct) > 0 {
+			e, a := unmatchedExp[0], unmatchedAct[0]
+			discrepancies = append(discrepancies, Discrepancy{Kind: KindMismatched, Pid: k.pid, Operation: k.operation, Expected: &e, Actual: &a})
+			unmatchedExp, unmatchedAct = unmatchedExp[1:], unmatchedAct[1:]
+		}
+		for _, e := range unmatchedExp {
+			discrepancies = append(discrepancies, Discrepancy{Kind: KindMissing, Pid: k.pid, Operation: k.operation, Expected: &e})
+		}
+		for _, a := range unmatchedAct {
+			discrepancies = append(discrepancies, Discrepancy{Kind: KindUnexpected, Pid: k.pid, Operation: k.operation, Actual: &a})
+		}
+	}
+
+	return discrepancies
+}
+
+func sameAmount(a, b Entry) bool {
+	return a.Amount == b.Amount && a.Currency == b.Currency
+}
+
+func hasSameAmount(entries []Entry, a Entry) bool {
+	for _, e := range entries {
+		if sameAmount(e, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// Republish writes the events of every missing entry to the outbox in one
+// transaction; the money_movement relay publishes them to Kafka from there.
+// Each missing pid and operation is compared again across all dates first, so
+// that an event the ledger recorded outside the reconciled days, or in the
+// meantime, is not published twice.
+func Republish(mmDB, ledgerDB *sql.DB, discrepancies []Discrepancy) (int, error) {
+	type key struct{ pid, operation string }
+	missing := make(map[key]bool)
+	var pids []string
+	for _, d := range discrepancies {
+		if d.Kind != KindMissing {
+			continue
+		}
+		if !slices.Contains(pids, d.Pid) {
+			pids = append(pids, d.Pid)
+		}
+		missing[key{d.Pid, d.Operation}] = true
+	}
+
+	var msgs []producer.LedgerMsg
+	for _, pid := range pids {
+		expected, err := fetchTransactions(mmDB, selectTransactionsByPidQuery, pid)
+		if err != nil {
+			return 0, err
+		}
+		actual, err := fetchLedger(ledgerDB, selectLedgerByPidQuery, pid)
+		if err != nil {
+			return 0, err
+		}
+		for _, d := range Compare(expected, actual) {
+			if d.Kind == KindMissing && missing[key{d.Pid, d.Operation}] {
+				msgs = append(msgs, d.Expected.msg)
+			}
+		}
+	}
+
+	tx, err := mmDB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	for _, msg := range msgs {
+		err = producer.EnqueueLedgerMessage(tx, msg)
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return 0, fmt.Errorf("failed to rollback transaction: %w", rollbackErr)
+			}
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(msgs), nil
+}
@@ -0,0 +1,112 @@
+package reconcile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MikePham0630/gomicro/internal/producer"
+)
+
+func TestCompare(t *testing.T) {
+	capture := func(id int64, amount int64) Entry {
+		return Entry{Id: id, Pid: "p1", UserId: "customer", Operation: producer.LedgerOperationCapture, Amount: amount, Currency: "USD", Date: "2026-10-17"}
+	}
+	refund := func(id int64, amount int64) Entry {
+		return Entry{Id: id, Pid: "p1", UserId: "customer", Operation: producer.LedgerOperationRefund, Amount: amount, Currency: "USD", Date: "2026-10-17"}
+	}
+	withCurrency := func(e Entry, currency string) Entry {
+		e.Currency = currency
+		return e
+	}
+	withPid := func(e Entry, pid string) Entry {
+		e.Pid = pid
+		return e
+	}
+
+	type discrepancy struct {
+		kind, pid, operation string
+		expectedId, actualId int64
+	}
+	tests := []struct {
+		name     string
+		expected []Entry
+		actual   []Entry
+		want     []discrepancy
+	}{
+		{
+			name:     "matched",
+			expected: []Entry{capture(1, 100), refund(2, 40)},
+			actual:   []Entry{refund(11, 40), capture(10, 100)},
+		},
+		{
+			name:     "multiple captures of the same amount match one to one",
+			expected: []Entry{capture(1, 100), capture(2, 100)},
+			actual:   []Entry{capture(10, 100), capture(11, 100)},
+		},
+		{
+			name:     "missing",
+			expected: []Entry{capture(1, 100), refund(2, 40)},
+			actual:   []Entry{capture(10, 100)},
+			want:     []discrepancy{{KindMissing, "p1", producer.LedgerOperationRefund, 2, 0}},
+		},
+		{
+			name:     "second capture of the same amount missing",
+			expected: []Entry{capture(1, 100), capture(2, 100)},
+			actual:   []Entry{capture(10, 100)},
+			want:     []discrepancy{{KindMissing, "p1", producer.LedgerOperationCapture, 2, 0}},
+		},
+		{
+			name:     "duplicated",
+			expected: []Entry{capture(1, 100)},
+			actual:   []Entry{capture(10, 100), capture(11, 100)},
+			want:     []discrepancy{{KindDuplicated, "p1", producer.LedgerOperationCapture, 0, 11}},
+		},
+		{
+			name:     "mismatched amount",
+			expected: []Entry{capture(1, 100)},
+			actual:   []Entry{capture(10, 90)},
+			want:     []discrepancy{{KindMismatched, "p1", producer.LedgerOperationCapture, 1, 10}},
+		},
+		{
+			name:     "mismatched currency",
+			expected: []Entry{capture(1, 100)},
+			actual:   []Entry{withCurrency(capture(10, 100), "EUR")},
+			want:     []discrepancy{{KindMismatched, "p1", producer.LedgerOperationCapture, 1, 10}},
+		},
+		{
+			name:     "unexpected",
+			expected: []Entry{capture(1, 100)},
+			actual:   []Entry{capture(10, 100), refund(11, 40)},
+			want:     []discrepancy{{KindUnexpected, "p1", producer.LedgerOperationRefund, 0, 11}},
+		},
+		{
+			name:     "sorted by pid and operation",
+			expected: []Entry{withPid(capture(1, 100), "p2"), refund(2, 40), capture(3, 100)},
+			actual:   []Entry{withPid(capture(10, 90), "p2")},
+			want: []discrepancy{
+				{KindMissing, "p1", producer.LedgerOperationRefund, 2, 0},
+				{KindMissing, "p1", producer.LedgerOperationCapture, 3, 0},
+				{KindMismatched, "p2", producer.LedgerOperationCapture, 1, 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []discrepancy
+			for _, d := range Compare(tt.expected, tt.actual) {
+				g := discrepancy{kind: d.Kind, pid: d.Pid, operation: d.Operation}
+				if d.Expected != nil {
+					g.expectedId = d.Expected.Id
+				}
+				if d.Actual != nil {
+					g.actualId = d.Actual.Id
+				}
+				got = append(got, g)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
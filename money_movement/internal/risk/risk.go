@@ -0,0 +1,244 @@
+// Package risk scores authorizations before any funds move. An Engine runs an
+// ordered list of rules loaded from a JSON file; each rule that matches adds
+// its reason and can escalate the outcome from allow to review or deny.
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/MikePham0630/gomicro/internal/currency"
+)
+
+// Outcomes, in increasing order of severity
+const (
+	Allow  = "ALLOW"
+	Review = "REVIEW"
+	Deny   = "DENY"
+)
+
+var severity = map[string]int{Allow: 0, Review: 1, Deny: 2}
+
+// Input is what the rules know about an authorization.
+type Input struct {
+	CustomerUserId  string
+	MerchantUserIds []string // one per leg of a split payment
+	Cents           int64
+	Currency        string
+	WalletCreatedAt time.Time // customer wallet
+	Now             time.Time
+}
+
+type Decision struct {
+	Outcome string
+	Reasons []string
+}
+
+// Rule is a single check. Match reports whether the authorization is risky
+// according to the rule.
+type Rule interface {
+	Match(in Input) bool
+}
+
+type configuredRule struct {
+	rule    Rule
+	outcome string
+	reason  string
+}
+
+// Engine evaluates its rules in the order they were configured. A nil Engine
+// allows everything.
+type Engine struct {
+	rules []configuredRule
+}
+
+// Evaluate runs the rules in order and returns the most severe outcome of the
+// rules that matched, with their reasons. Evaluation stops at the first rule
+// that denies.
+func (e *Engine) Evaluate(in Input) Decision {
+	d := Decision{Outcome: Allow}
+	if e == nil {
+		return d
+	}
+	for _, r := range e.rules {
+		if !r.rule.Match(in) {
+			continue
+		}
+		d.Reasons = append(d.Reasons, r.reason)
+		if severity[r.outcome] > severity[d.Outcome] {
+			d.Outcome = r.outcome
+		}
+		if d.Outcome == Deny {
+			break
+		}
+	}
+	return d
+}
+
+// config is the format of the rules file:
+//
+//	{"rules": [
+//	  {"type": "amount_above", "outcome": "REVIEW", "reason": "LARGE_AMOUNT", "params": {"currency": "USD", "cents": 100000}},
+//	  {"type": "merchant_blocklist", "outcome": "DENY", "reason": "BLOCKED_MERCHANT", "params": {"merchants": ["mallory"]}}
+//	]}
+type config struct {
+	Rules []struct {
+		Type    string          `json:"type"`
+		Outcome string          `json:"outcome"`
+		Reason  string          `json:"reason"`
+		Params  json.RawMessage `json:"params"`
+	} `json:"rules"`
+}
+
+// ruleTypes builds a rule of each supported type from its params.
+var ruleTypes = map[string]func(params json.RawMessage) (Rule, error){
+	"amount_above":       newAmountAbove,
+	"wallet_age_below":   newWalletAgeBelow,
+	"merchant_blocklist": newMerchantBlocklist,
+	"hour_between":       newHourBetween,
+}
+
+// Load reads the rules file at path.
+func Load(path string) (*Engine, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk rules: %w", err)
+	}
+	return Parse(b)
+}
+
+func Parse(b []byte) (*Engine, error) {
+	var c config
+	err := json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode risk rules: %w", err)
+	}
+
+	e := &Engine{}
+	for i, r := range c.Rules {
+		newRule, ok := ruleTypes[r.Type]
+		if !ok {
+			return nil, fmt.Errorf("rule %d: unsupported type %q", i, r.Type)
+		}
+		if r.Outcome != Review && r.Outcome != Deny {
+			return nil, fmt.Errorf("rule %d: outcome must be %s or %s, got %q", i, Review, Deny, r.Outcome)
+		}
+		if r.Reason == "" {
+			return nil, fmt.Errorf("rule %d: reason is required", i)
+		}
+		rule, err := newRule(r.Params)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i, r.Type, err)
+		}
+		e.rules = append(e.rules, configuredRule{rule: rule, outcome: r.Outcome, reason: r.Reason})
+	}
+	return e, nil
+}
+
+// amountAbove matches authorizations in Currency of more than Cents. Cents
+// are minor units, so a threshold only makes sense for a single currency and
+// authorizations in other currencies never match.
+type amountAbove struct {
+	Currency string `json:"currency"`
+	Cents    int64  `json:"cents"`
+}
+
+func newAmountAbove(params json.RawMessage) (Rule, error) {
+	var r amountAbove
+	err := json.Unmarshal(params, &r)
+	if err != nil {
+		return nil, err
+	}
+	err = currency.Validate(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if r.Cents <= 0 {
+		return nil, fmt.Errorf("cents must be positive")
+	}
+	return r, nil
+}
+
+func (r amountAbove) Match(in Input) bool {
+	return r.Currency == in.Currency && in.Cents > r.Cents
+}
+
+// walletAgeBelow matches customers whose wallet was created less than Age ago.
+type walletAgeBelow struct {
+	age time.Duration
+}
+
+func newWalletAgeBelow(params json.RawMessage) (Rule, error) {
+	var p struct {
+		Age string `json:"age"` // e.g. "72h"
+	}
+	err := json.Unmarshal(params, &p)
+	if err != nil {
+		return nil, err
+	}
+	age, err := time.ParseDuration(p.Age)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age: %w", err)
+	}
+	if age <= 0 {
+		return nil, fmt.Errorf("age must be positive")
+	}
+	return walletAgeBelow{age: age}, nil
+}
+
+func (r walletAgeBelow) Match(in Input) bool {
+	return in.Now.Sub(in.WalletCreatedAt) < r.age
+}
+
+// merchantBlocklist matches payments to any of Merchants.
+type merchantBlocklist struct {
+	Merchants []string `json:"merchants"` // merchant user ids
+}
+
+func newMerchantBlocklist(params json.RawMessage) (Rule, error) {
+	var r merchantBlocklist
+	err := json.Unmarshal(params, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r merchantBlocklist) Match(in Input) bool {
+	for _, merchantUserId := range in.MerchantUserIds {
+		if slices.Contains(r.Merchants, merchantUserId) {
+			return true
+		}
+	}
+	return false
+}
+
+// hourBetween matches authorizations made from Start up to End o'clock UTC.
+// The window wraps around midnight when Start is after End.
+type hourBetween struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+func newHourBetween(params json.RawMessage) (Rule, error) {
+	var r hourBetween
+	err := json.Unmarshal(params, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Start < 0 || r.Start > 23 || r.End < 0 || r.End > 23 {
+		return nil, fmt.Errorf("start and end must be hours from 0 to 23")
+	}
+	return r, nil
+}
+
+func (r hourBetween) Match(in Input) bool {
+	hour := in.Now.UTC().Hour()
+	if r.Start <= r.End {
+		return hour >= r.Start && hour < r.End
+	}
+	return hour >= r.Start || hour < r.End
+}
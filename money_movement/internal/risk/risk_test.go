@@ -0,0 +1,250 @@
+package risk
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr string
+	}{
+		{
+			name:    "malformed json",
+			rules:   `{"rules": [`,
+			wantErr: "failed to decode",
+		},
+		{
+			name:    "unsupported type",
+			rules:   `{"type": "velocity", "outcome": "DENY", "reason": "R", "params": {}}`,
+			wantErr: "unsupported type",
+		},
+		{
+			name:    "allow outcome",
+			rules:   `{"type": "amount_above", "outcome": "ALLOW", "reason": "R", "params": {"currency": "USD", "cents": 100}}`,
+			wantErr: "outcome must be",
+		},
+		{
+			name:    "lower case outcome",
+			rules:   `{"type": "amount_above", "outcome": "deny", "reason": "R", "params": {"currency": "USD", "cents": 100}}`,
+			wantErr: "outcome must be",
+		},
+		{
+			name:    "missing reason",
+			rules:   `{"type": "amount_above", "outcome": "DENY", "params": {"currency": "USD", "cents": 100}}`,
+			wantErr: "reason is required",
+		},
+		{
+			name:    "non-positive amount",
+			rules:   `{"type": "amount_above", "outcome": "DENY", "reason": "R", "params": {"currency": "USD", "cents": 0}}`,
+			wantErr: "cents must be positive",
+		},
+		{
+			name:    "amount without currency",
+			rules:   `{"type": "amount_above", "outcome": "DENY", "reason": "R", "params": {"cents": 100}}`,
+			wantErr: "unsupported currency",
+		},
+		{
+			name:    "amount in unsupported currency",
+			rules:   `{"type": "amount_above", "outcome": "DENY", "reason": "R", "params": {"currency": "XYZ", "cents": 100}}`,
+			wantErr: "unsupported currency",
+		},
+		{
+			name:    "invalid age",
+			rules:   `{"type": "wallet_age_below", "outcome": "REVIEW", "reason": "R", "params": {"age": "3 days"}}`,
+			wantErr: "invalid age",
+		},
+		{
+			name:    "missing age",
+			rules:   `{"type": "wallet_age_below", "outcome": "REVIEW", "reason": "R", "params": {}}`,
+			wantErr: "invalid age",
+		},
+		{
+			name:    "negative age",
+			rules:   `{"type": "wallet_age_below", "outcome": "REVIEW", "reason": "R", "params": {"age": "-1h"}}`,
+			wantErr: "age must be positive",
+		},
+		{
+			name:    "hour out of range",
+			rules:   `{"type": "hour_between", "outcome": "REVIEW", "reason": "R", "params": {"start": 22, "end": 24}}`,
+			wantErr: "start and end must be hours",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := tt.rules
+			if !strings.HasPrefix(rules, `{"rules"`) {
+				rules = `{"rules": [` + rules + `]}`
+			}
+			_, err := Parse([]byte(rules))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	engine, err := Parse([]byte(`{"rules": [
+		{"type": "amount_above", "outcome": "REVIEW", "reason": "LARGE_AMOUNT", "params": {"currency": "USD", "cents": 100000}},
+		{"type": "wallet_age_below", "outcome": "REVIEW", "reason": "NEW_WALLET", "params": {"age": "72h"}},
+		{"type": "merchant_blocklist", "outcome": "DENY", "reason": "BLOCKED_MERCHANT", "params": {"merchants": ["mallory"]}},
+		{"type": "hour_between", "outcome": "REVIEW", "reason": "NIGHT", "params": {"start": 22, "end": 6}},
+		{"type": "amount_above", "outcome": "DENY", "reason": "HUGE_AMOUNT", "params": {"currency": "USD", "cents": 1000000}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	input := func(change func(in *Input)) Input {
+		in := Input{
+			CustomerUserId:  "customer",
+			MerchantUserIds: []string{"merchant"},
+			Cents:           500,
+			Currency:        "USD",
+			WalletCreatedAt: noon.AddDate(0, -1, 0),
+			Now:             noon,
+		}
+		if change != nil {
+			change(&in)
+		}
+		return in
+	}
+	at := func(hour, minute int) func(in *Input) {
+		return func(in *Input) {
+			in.Now = time.Date(2026, 10, 17, hour, minute, 0, 0, time.UTC)
+		}
+	}
+
+	tests := []struct {
+		name string
+		in   Input
+		want Decision
+	}{
+		{
+			name: "nothing matches",
+			in:   input(nil),
+			want: Decision{Outcome: Allow},
+		},
+		{
+			name: "amount in the rule's currency",
+			in:   input(func(in *Input) { in.Cents = 100001 }),
+			want: Decision{Outcome: Review, Reasons: []string{"LARGE_AMOUNT"}},
+		},
+		{
+			name: "amount in another currency",
+			in:   input(func(in *Input) { in.Cents, in.Currency = 100001, "EUR" }),
+			want: Decision{Outcome: Allow},
+		},
+		{
+			name: "same cents in a currency without decimals",
+			in:   input(func(in *Input) { in.Cents, in.Currency = 2000000, "VND" }),
+			want: Decision{Outcome: Allow},
+		},
+		{
+			name: "new wallet",
+			in:   input(func(in *Input) { in.WalletCreatedAt = noon.Add(-71 * time.Hour) }),
+			want: Decision{Outcome: Review, Reasons: []string{"NEW_WALLET"}},
+		},
+		{
+			name: "blocked merchant on one leg",
+			in:   input(func(in *Input) { in.MerchantUserIds = []string{"merchant", "mallory"} }),
+			want: Decision{Outcome: Deny, Reasons: []string{"BLOCKED_MERCHANT"}},
+		},
+		{
+			name: "window start before midnight",
+			in:   input(at(22, 0)),
+			want: Decision{Outcome: Review, Reasons: []string{"NIGHT"}},
+		},
+		{
+			name: "window after midnight",
+			in:   input(at(5, 59)),
+			want: Decision{Outcome: Review, Reasons: []string{"NIGHT"}},
+		},
+		{
+			name: "window end is exclusive",
+			in:   input(at(6, 0)),
+			want: Decision{Outcome: Allow},
+		},
+		{
+			name: "just before the window",
+			in:   input(at(21, 59)),
+			want: Decision{Outcome: Allow},
+		},
+		{
+			name: "window in UTC",
+			in: input(func(in *Input) {
+				in.Now = time.Date(2026, 10, 18, 9, 0, 0, 0, time.FixedZone("UTC+10", 10*60*60))
+			}),
+			want: Decision{Outcome: Review, Reasons: []string{"NIGHT"}},
+		},
+		{
+			name: "reviews add up and a deny stops evaluation",
+			in: input(func(in *Input) {
+				in.Cents = 2000000
+				in.WalletCreatedAt = noon
+				in.MerchantUserIds = []string{"mallory"}
+				in.Now = noon.Add(11 * time.Hour)
+			}),
+			want: Decision{Outcome: Deny, Reasons: []string{"LARGE_AMOUNT", "NEW_WALLET", "BLOCKED_MERCHANT"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.Evaluate(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestShippedRules checks that the default rules apply thresholds of about the
+// same value in every currency rather than the same number of minor units.
+func TestShippedRules(t *testing.T) {
+	engine, err := Load("../../risk_rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := []string{"AUD", "BHD", "CAD", "CHF", "CNY", "EUR", "GBP", "HKD", "JPY", "KRW", "KWD", "NZD", "SEK", "SGD", "USD", "VND"}
+	for _, code := range codes {
+		t.Run(code, func(t *testing.T) {
+			in := Input{Cents: 1, Currency: code, WalletCreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+			if got := engine.Evaluate(in); got.Outcome != Allow {
+				t.Errorf("1 minor unit of %s decided %+v, want %s", code, got, Allow)
+			}
+			in.Cents = math.MaxInt64
+			if got := engine.Evaluate(in); got.Outcome != Deny {
+				t.Errorf("huge %s amount decided %+v, want %s", code, got, Deny)
+			}
+		})
+	}
+	in := Input{Cents: 2600000, Currency: "VND", WalletCreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+	if got := engine.Evaluate(in); got.Outcome != Allow {
+		t.Errorf("VND 2,600,000 decided %+v, want %s", got, Allow)
+	}
+}
+
+func TestEvaluateWithoutRules(t *testing.T) {
+	var engine *Engine
+	if got := engine.Evaluate(Input{Cents: 1}); got.Outcome != Allow {
+		t.Errorf("nil engine decided %s, want %s", got.Outcome, Allow)
+	}
+}
+
+func TestHourBetweenSameDay(t *testing.T) {
+	r := hourBetween{Start: 9, End: 17}
+	for hour, want := range map[int]bool{8: false, 9: true, 16: true, 17: false} {
+		in := Input{Now: time.Date(2026, 10, 17, hour, 30, 0, 0, time.UTC)}
+		if got := r.Match(in); got != want {
+			t.Errorf("Match at %d:30 = %t, want %t", hour, got, want)
+		}
+	}
+}